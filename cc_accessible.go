@@ -0,0 +1,250 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ========================= ACCESSIBLE MODE =========================
+
+// The accessible mode replaces the full-screen menu with numbered prompts for screen readers.
+// Nothing is redrawn: every state change is announced as a new line, input is read line by line.
+
+// accessibleReader reads the answers, shared so buffered input isn't lost between prompts
+var accessibleReader = bufio.NewReader(os.Stdin)
+
+// ask prints a prompt on its own line and returns the trimmed answer, ok is false at the end of input
+func ask(prompt string) (string, bool) {
+	fmt.Printf("%s\n> ", prompt)
+	answer, err := accessibleReader.ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Println()
+		return "", false
+	}
+	return strings.TrimSpace(answer), true
+}
+
+// accessibleRow describes the visible entry i, numbered from 1
+func accessibleRow(m *menuState, i int) string {
+	p := m.programs[m.visible[i]]
+	state := tr("a11y.unselected")
+	if p.Checked {
+		state = tr("a11y.selected")
+	}
+	return tr("a11y.row", i+1, p.Name, formatMB(p.Size), state)
+}
+
+// listAccessible prints every visible entry with its number
+func listAccessible(m *menuState) {
+	checked := 0
+	for _, p := range m.programs {
+		if p.Checked {
+			checked++
+		}
+	}
+	fmt.Println(tr("a11y.found", len(m.visible), checked))
+	for i := range m.visible {
+		fmt.Println(accessibleRow(m, i))
+	}
+}
+
+// parseItems turns "3", "1 4" or "2-5,7" into indexes of visible entries
+func parseItems(s string, n int) ([]int, error) {
+	var items []int
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' }) {
+		from, to, isRange := strings.Cut(f, "-")
+		a, err := strconv.Atoi(from)
+		b := a
+		if err == nil && isRange {
+			b, err = strconv.Atoi(to)
+		}
+		if err != nil || a < 1 || b > n || a > b {
+			return nil, errors.New(tr("a11y.invalid", f, n))
+		}
+		for i := a; i <= b; i++ {
+			items = append(items, i-1)
+		}
+	}
+	return items, nil
+}
+
+// accessibleMenu is the line based counterpart of handleMenu
+func accessibleMenu(preset string) {
+	showBanner()
+	existing := scanWithSpinner()
+	if len(existing) == 0 {
+		fmt.Println(tr("menu.none_found"))
+		cc_exit(EXIT_NOTHING)
+	}
+	if preset != "" {
+		if err := applyPreset(existing, preset); err != nil {
+			fmt.Println(err)
+			cc_exit(EXIT_USAGE)
+		}
+	} else {
+		preselect(existing)
+	}
+
+	m := newMenuState(existing, preset)
+	listAccessible(m)
+	exitCode := EXIT_NOTHING // Of the last cleanup, used when quitting
+	for {
+		answer, ok := ask(tr("a11y.prompt", len(m.visible)))
+		if !ok {
+			cc_exit(exitCode)
+		}
+		cmd, arg, _ := strings.Cut(answer, " ")
+		arg = strings.TrimSpace(arg)
+		switch strings.ToLower(cmd) {
+		case "":
+		case "a":
+			m.toggleAll()
+			m.preset = ""
+			if anyChecked(m.programs) {
+				fmt.Println(tr("a11y.all_on", len(m.visible)))
+			} else {
+				fmt.Println(tr("a11y.all_off", len(m.visible)))
+			}
+		case "p":
+			names := presetNames()
+			if arg == "" {
+				// Like the preset key in the menu: the next preset, after the last one nothing
+				next := 0
+				for i, n := range names {
+					if n == m.preset {
+						next = i + 1
+					}
+				}
+				if next == len(names) {
+					m.preset = ""
+					checkNames(m.programs, nil, true)
+					fmt.Println(tr("a11y.preset_none"))
+					continue
+				}
+				arg = names[next]
+			}
+			if err := applyPreset(m.programs, arg); err != nil {
+				fmt.Println(err)
+				continue
+			}
+			m.preset = arg
+			selected := 0
+			for _, p := range m.programs {
+				if p.Checked {
+					selected++
+				}
+			}
+			fmt.Println(tr("a11y.preset", arg, selected))
+		case "f":
+			m.filter = arg
+			m.refresh()
+			listAccessible(m)
+		case "i":
+			items, err := parseItems(arg, len(m.visible))
+			if err != nil || len(items) == 0 {
+				fmt.Println(tr("a11y.invalid", arg, len(m.visible)))
+				continue
+			}
+			for _, i := range items {
+				p := m.programs[m.visible[i]]
+				fmt.Println(tr("detail.title", p.Name, len(p.Matches), p.files()))
+				for _, mt := range p.Matches {
+					fmt.Println(tr("a11y.path", mt.Path, formatMB(mt.Size), mt.Files))
+				}
+			}
+		case "l":
+			listAccessible(m)
+		case "h", "?":
+			for _, key := range []string{"a11y.help_items", "a11y.help_all", "a11y.help_preset", "a11y.help_filter",
+				"a11y.help_info", "a11y.help_list", "a11y.help_clean", "a11y.help_quit"} {
+				fmt.Println(tr(key))
+			}
+		case "q":
+			cc_exit(exitCode)
+		case "c":
+			if anyChecked(m.programs) && !confirmAccessible(m.programs) {
+				fmt.Println(tr("a11y.cancelled"))
+				continue
+			}
+			result := runCleanup(m.programs)
+			exitCode = result.ExitCode()
+			if result.Cancelled {
+				cc_exit(exitCode)
+			}
+			if again, ok := ask(tr("a11y.again")); !ok || !strings.EqualFold(again, "c") {
+				cc_exit(exitCode)
+			}
+			fresh := scanWithSpinner()
+			if len(fresh) == 0 {
+				fmt.Println(tr("menu.none_left"))
+				cc_exit(exitCode)
+			}
+			m.replace(fresh)
+			listAccessible(m)
+		default:
+			items, err := parseItems(answer, len(m.visible))
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			for _, i := range items {
+				p := &m.programs[m.visible[i]]
+				p.Checked = !p.Checked
+				m.preset = ""
+				fmt.Println(accessibleRow(m, i))
+			}
+		}
+	}
+}
+
+// confirmAccessible reads out the confirmation screen and asks whether to clean
+func confirmAccessible(programs []Program) bool {
+	lines := confirmLines(summarize(programs), len(programs)+100)
+	// The last line lists the keys of the full-screen version
+	for _, l := range lines[:len(lines)-1] {
+		if l != "" {
+			fmt.Println(strings.TrimSpace(l))
+		}
+	}
+	for {
+		answer, ok := ask(tr("a11y.confirm"))
+		switch strings.ToLower(answer) {
+		case "y":
+			return true
+		case "d":
+			*Flagdryrun = !*Flagdryrun
+			mode := tr("confirm.delete")
+			if *Flagdryrun {
+				mode = tr("confirm.dry")
+			}
+			fmt.Println(tr("confirm.mode", mode))
+		default:
+			if !ok || answer == "n" || answer == "N" {
+				return false
+			}
+		}
+	}
+}
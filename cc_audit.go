@@ -0,0 +1,172 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditLog appends one JSON object per line for every path the cleanup removes or skips
+type AuditLog struct {
+	mu      sync.Mutex
+	f       *os.File
+	session string
+	program string // Program currently being cleaned
+}
+
+// auditLog is set from -audit-log or [audit] path, nil disables auditing
+var auditLog *AuditLog
+
+// AuditSession is the header written at the start of every cleanup session
+type AuditSession struct {
+	Type           string    `json:"type"` // Always "session"
+	Session        string    `json:"session"`
+	Time           time.Time `json:"time"`
+	User           string    `json:"user"`
+	Host           string    `json:"host"`
+	Version        string    `json:"version"`
+	CatalogVersion string    `json:"catalog_version"`
+	DryRun         bool      `json:"dry_run"`
+	Config         *Config   `json:"config"`
+}
+
+// AuditRecord describes a single removed or skipped path
+type AuditRecord struct {
+	Type    string    `json:"type"` // Always "path"
+	Session string    `json:"session"`
+	Time    time.Time `json:"time"`
+	Program string    `json:"program"`
+	Action  string    `json:"action"` // "removed" or "skipped"
+	Path    string    `json:"path"`
+	Dir     bool      `json:"dir,omitempty"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mtime"`
+	UID     *int      `json:"uid,omitempty"` // Not available on Windows
+	Reason  string    `json:"reason"`
+}
+
+// openAuditLog opens path for appending, the file is only readable by the owner
+func openAuditLog(path string) (*AuditLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditLog{f: f}, nil
+}
+
+func (a *AuditLog) write(v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.f.Write(append(data, '\n')); err != nil {
+		logWarn(tr("audit.error", err))
+	}
+}
+
+// startSession writes the session header and starts a new session id
+func (a *AuditLog) startSession(dryRun bool) {
+	host, _ := os.Hostname()
+	now := time.Now()
+	a.session = fmt.Sprintf("%s-%d", now.Format("20060102T150405"), os.Getpid())
+	a.write(AuditSession{
+		Type:           "session",
+		Session:        a.session,
+		Time:           now,
+		User:           currentUsername(),
+		Host:           host,
+		Version:        CC_VERSION,
+		CatalogVersion: catalogVersion(),
+		DryRun:         dryRun,
+		Config:         cfg,
+	})
+}
+
+// record logs one path, info may be nil if the path couldn't be read
+func (a *AuditLog) record(action, path string, info os.FileInfo, reason string) {
+	r := AuditRecord{
+		Type:    "path",
+		Session: a.session,
+		Time:    time.Now(),
+		Program: a.program,
+		Action:  action,
+		Path:    path,
+		Reason:  reason,
+	}
+	if info != nil {
+		r.Dir = info.IsDir()
+		r.Size = info.Size()
+		r.ModTime = info.ModTime()
+		if uid, ok := fileOwner(info); ok {
+			r.UID = &uid
+		}
+	}
+	a.write(r)
+}
+
+// catalogVersion identifies the cache catalog of this build and OS: the program version
+// plus a short hash over every Program name, category and raw path pattern, so the same
+// catalog yields the same version for every user and machine
+func catalogVersion() string {
+	h := sha256.New()
+	for _, p := range getPrograms() {
+		fmt.Fprintf(h, "%s\x00%s\x00", p.Name, p.Category)
+		for _, path := range p.Paths {
+			fmt.Fprintf(h, "%s\x00", catalogPattern(path))
+		}
+		h.Write([]byte{0})
+	}
+	return CC_VERSION + "-" + hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// catalogPattern undoes the expansion getPrograms applies to the built-in paths, putting the
+// home directory back as '~' and the Windows folders back as their %VARIABLE%
+func catalogPattern(path string) string {
+	roots := []string{"LOCALAPPDATA", "APPDATA", "ProgramFiles(x86)", "ProgramFiles", "WINDIR"}
+	if runtime.GOOS == "windows" {
+		for _, env := range roots {
+			if dir := os.Getenv(env); dir != "" {
+				if rel, err := filepath.Rel(dir, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+					return "%" + env + "%/" + filepath.ToSlash(rel)
+				}
+			}
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		if rel, err := filepath.Rel(home, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "~/" + filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(path)
+}
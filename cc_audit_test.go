@@ -0,0 +1,55 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestCatalogVersionIgnoresHome(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the Windows catalog is rooted in %APPDATA% and friends")
+	}
+	t.Setenv("HOME", "/home/alice")
+	alice := catalogVersion()
+	t.Setenv("HOME", "/var/lib/bob")
+	if bob := catalogVersion(); bob != alice {
+		t.Errorf("catalog version depends on the home directory: %s != %s", alice, bob)
+	}
+}
+
+func TestCatalogPattern(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX paths")
+	}
+	t.Setenv("HOME", "/home/alice")
+	tests := map[string]string{
+		"/home/alice/.cache/thumbnails": "~/.cache/thumbnails",
+		"~/.cache/pip":                  "~/.cache/pip",
+		"/home/alicia/.cache":           "/home/alicia/.cache",
+		"/var/cache/apt/archives":       "/var/cache/apt/archives",
+	}
+	for path, want := range tests {
+		if got := catalogPattern(path); got != want {
+			t.Errorf("catalogPattern(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/eiannone/keyboard"
+)
+
+// browseEntry is one row of the browser: a matched path or something inside it
+type browseEntry struct {
+	PathInfo
+	dir bool
+}
+
+// browser is an ncdu-like view of the paths a Program matches, where sub-folders can be excluded
+type browser struct {
+	p       *Program
+	dir     string // Directory shown, "" for the list of matched paths
+	entries []browseEntry
+	idx     int
+	view    viewport
+	cache   map[string][]browseEntry
+}
+
+// load lists dir (or the matched paths), largest first
+func (b *browser) load(dir string) {
+	b.dir, b.idx, b.view.top = dir, 0, 0
+	if cached, ok := b.cache[dir]; ok {
+		b.entries = cached
+		return
+	}
+
+	var entries []browseEntry
+	if dir == "" {
+		for _, m := range b.p.Matches {
+			info, err := os.Stat(m.Path)
+			entries = append(entries, browseEntry{m, err == nil && info.IsDir()})
+		}
+		sortBySize(entries)
+	} else {
+		entries = listDir(dir)
+	}
+	b.cache[dir] = entries
+	b.entries = entries
+}
+
+// listDir sizes everything in dir, largest first
+func listDir(dir string) []browseEntry {
+	var entries []browseEntry
+	list, _ := os.ReadDir(dir)
+	for _, e := range list {
+		info, _ := statPath(filepath.Join(dir, e.Name()), nil)
+		entries = append(entries, browseEntry{info, e.IsDir()})
+	}
+	sortBySize(entries)
+	return entries
+}
+
+func sortBySize(entries []browseEntry) {
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Size > entries[j].Size })
+}
+
+// largestEntry returns the biggest size in entries, at least 1, to scale the size bars
+func largestEntry(entries []browseEntry) int64 {
+	largest := int64(1)
+	for _, e := range entries {
+		largest = max(largest, e.Size)
+	}
+	return largest
+}
+
+// sizeRow formats one browser line: cursor, checkbox, size, a 10 cell bar relative to largest and the name
+func sizeRow(selected bool, check string, e browseEntry, largest int64, name string) string {
+	cursor := "    "
+	if selected {
+		cursor = YELLOW + "  >_" + RC
+	}
+	if e.dir {
+		name += string(filepath.Separator)
+	}
+	filled := int(e.Size * 10 / largest)
+	return fmt.Sprintf("%s%s %s%10s [%s%s]%s %s",
+		cursor, check, YELLOW, formatMB(e.Size), strings.Repeat("#", filled), strings.Repeat("-", 10-filled), RC, name)
+}
+
+// moveCursor applies the navigation keys of the keymap and the scroll wheel, shared by the list screens, to idx in a list of n rows.
+// ok is false for every other key.
+func moveCursor(ev inputEvent, idx, n, page int) (next int, ok bool) {
+	last := max(n-1, 0)
+	if ev.Mouse != nil {
+		switch ev.Mouse.Button {
+		case MOUSE_WHEEL_UP:
+			return max(idx-MOUSE_WHEEL_LINES, 0), true
+		case MOUSE_WHEEL_DOWN:
+			return min(idx+MOUSE_WHEEL_LINES, last), true
+		}
+		return idx, false
+	}
+	switch bindings.action(ev) {
+	case ACT_UP:
+		return max(idx-1, 0), true
+	case ACT_DOWN:
+		return min(idx+1, last), true
+	case ACT_PAGE_UP:
+		return max(idx-page, 0), true
+	case ACT_PAGE_DOWN:
+		return min(idx+page, last), true
+	case ACT_TOP:
+		return 0, true
+	case ACT_BOTTOM:
+		return last, true
+	}
+	return idx, false
+}
+
+// state returns the checkbox of a path: "X" cleaned, " " left alone, "~" partly excluded
+func (b *browser) state(path string) string {
+	if !b.p.Checked || isExcluded(path, b.p.Exclude) {
+		return " "
+	}
+	for _, e := range b.p.Exclude {
+		if strings.HasPrefix(e.Path, path+string(filepath.Separator)) {
+			return "~"
+		}
+	}
+	return "X"
+}
+
+// exclude leaves path out of the cleanup
+func (b *browser) exclude(path string) {
+	var keep []PathInfo
+	for _, e := range b.p.Exclude {
+		if !isExcluded(e.Path, []PathInfo{{Path: path}}) {
+			keep = append(keep, e)
+		}
+	}
+	info, _ := statPath(path, nil)
+	b.p.Exclude = append(keep, info)
+	b.p.resize(path)
+}
+
+// include makes sure path is cleaned. If it lies inside an excluded folder, that exclusion
+// is split up so that only the siblings along the way stay excluded.
+// For an unchecked Program this means cleaning nothing but path.
+func (b *browser) include(path string) {
+	if !b.p.Checked {
+		b.p.Checked = true
+		b.p.Exclude = append([]PathInfo(nil), b.p.Matches...)
+	}
+	for i, e := range b.p.Exclude {
+		if !isExcluded(path, []PathInfo{e}) {
+			continue
+		}
+		b.p.Exclude = append(b.p.Exclude[:i:i], b.p.Exclude[i+1:]...)
+		rel, err := filepath.Rel(e.Path, path)
+		if err != nil || rel == "." {
+			break
+		}
+		dir := e.Path
+		for _, part := range strings.Split(rel, string(filepath.Separator)) {
+			list, _ := os.ReadDir(dir)
+			for _, sib := range list {
+				if sib.Name() != part {
+					info, _ := statPath(filepath.Join(dir, sib.Name()), nil)
+					b.p.Exclude = append(b.p.Exclude, info)
+				}
+			}
+			dir = filepath.Join(dir, part)
+		}
+		break
+	}
+	b.p.resize(path)
+}
+
+// resize sizes the matches of p again after its exclusions changed at path ("" for all of them).
+// Like the cleanup they are sized with the exclusions applied, so retention and nested exclusions
+// are only counted once. Once everything is excluded the Program is unchecked instead.
+func (p *Program) resize(path string) {
+	all := true
+	for _, m := range p.Matches {
+		if !isExcluded(m.Path, p.Exclude) {
+			all = false
+		}
+	}
+	if all {
+		p.Checked, p.Exclude, path = false, nil, ""
+	}
+	p.Size = 0
+	for i, m := range p.Matches {
+		related := isExcluded(m.Path, []PathInfo{{Path: path}}) || isExcluded(path, []PathInfo{{Path: m.Path}})
+		if path == "" || related || isExcluded(m.Path, p.Exclude) {
+			p.Matches[i], _ = statPath(m.Path, p.Exclude)
+		}
+		p.Size += p.Matches[i].Size
+	}
+}
+
+// render draws the browser over the whole screen
+func (b *browser) render() {
+	cols, rows := screenSize()
+	where := b.dir
+	if where == "" {
+		where = tr("browse.matches")
+	}
+	fmt.Print(CLEAR_SCREEN)
+	fmt.Printf("%s\n", fitWidth(fmt.Sprintf("%s%s%s > %s", CYAN, b.p.Name, RC, where), cols))
+	fmt.Printf("%s\n", fitWidth(tr("browse.keys", bindings.label(ACT_BROWSE), bindings.label(ACT_PARENT), bindings.label(ACT_TOGGLE)), cols))
+
+	// Two header lines, the status line and a spare row
+	b.view.height = max(rows-4, 1)
+	b.view.follow(b.idx, len(b.entries))
+	largest := largestEntry(b.entries)
+	if len(b.entries) == 0 {
+		fmt.Printf("    %s\n", tr("list.empty"))
+	}
+	for i := b.view.top; i < b.view.end(len(b.entries)); i++ {
+		e := b.entries[i]
+		name := filepath.Base(e.Path)
+		if b.dir == "" {
+			name = e.Path
+		}
+		check := "[" + GREEN + b.state(e.Path) + RC + "]"
+		fmt.Printf("%s\n", fitWidth(sizeRow(i == b.idx, check, e, largest, name), cols))
+	}
+
+	var excluded int64
+	for _, e := range b.p.Exclude {
+		excluded += e.Size
+	}
+	status := tr("browse.status", YELLOW+formatMB(b.p.Size)+RC, len(b.p.Exclude), formatMB(excluded))
+	if !b.p.Checked {
+		status = tr("browse.unselected", bindings.label(ACT_TOGGLE))
+	}
+	if b.view.height < len(b.entries) {
+		status += " | " + tr("list.range", b.view.top+1, b.view.end(len(b.entries)), len(b.entries))
+	}
+	fmt.Printf("%s\n", fitWidth(status, cols))
+}
+
+// browse lets the user walk through the paths of p and exclude (or pick) sub-folders
+func browse(p *Program, keys <-chan inputEvent, resized <-chan struct{}) {
+	b := &browser{p: p, cache: map[string][]browseEntry{}}
+	b.load("")
+	b.render()
+	for {
+		var ev inputEvent
+		var ok bool
+		select {
+		case ev, ok = <-keys:
+		case <-resized:
+			b.render()
+			continue
+		}
+		if !ok || ev.Err != nil {
+			return
+		}
+		if idx, ok := moveCursor(ev, b.idx, len(b.entries), b.view.height); ok {
+			b.idx = idx
+			b.render()
+			continue
+		}
+		switch action := bindings.action(ev); {
+		case action == ACT_BROWSE:
+			if b.idx < len(b.entries) && b.entries[b.idx].dir {
+				b.load(b.entries[b.idx].Path)
+			}
+		case action == ACT_PARENT:
+			if b.dir == "" {
+				return
+			}
+			// Going up from a matched path leads back to the list of matches
+			from, up := b.dir, filepath.Dir(b.dir)
+			if isMatch(b.p, b.dir) {
+				up = ""
+			}
+			b.load(up)
+			for i, e := range b.entries {
+				if e.Path == from {
+					b.idx = i
+				}
+			}
+		case action == ACT_TOGGLE:
+			if b.idx < len(b.entries) {
+				if path := b.entries[b.idx].Path; b.state(path) == " " {
+					b.include(path)
+				} else {
+					b.exclude(path)
+				}
+			}
+		case ev.Key == keyboard.KeyEsc || action == "" && (ev.Rune == 'q' || ev.Rune == 'Q'):
+			return
+		case ev.Key == keyboard.KeyCtrlC:
+			cc_exit(EXIT_ABORTED)
+		default:
+			continue
+		}
+		b.render()
+	}
+}
+
+// isMatch reports whether path is one of the paths p matched
+func isMatch(p *Program, path string) bool {
+	for _, m := range p.Matches {
+		if m.Path == path {
+			return true
+		}
+	}
+	return false
+}
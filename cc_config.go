@@ -0,0 +1,366 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Config holds everything read from config.toml
+type Config struct {
+	Path string `json:"path"` // Where the config was loaded from
+
+	// [defaults]
+	Selected []string `json:"selected"` // Program names pre-checked in the menu
+	DryRun   bool     `json:"dry_run"`
+
+	// [retention]
+	MinAgeDays int      `json:"min_age_days"` // Only delete files older than this many days (0 = everything)
+	Keep       []string `json:"keep"`         // File name patterns that are never deleted
+
+	// [ui]
+	SkipInit     bool   `json:"skip_init"`     // Same as -t
+	RememberLast bool   `json:"remember_last"` // Pre-check the entries selected in the last run
+	Mouse        bool   `json:"mouse"`         // Report clicks and the scroll wheel, on by default
+	Language     string `json:"language"`      // Message catalog, detected from the locale if empty
+	Accessible   bool   `json:"accessible"`    // Same as -accessible
+
+	// [theme]
+	Color       string `json:"color"` // auto, always or never
+	Theme       string `json:"theme"` // Name of a built-in palette
+	ThemeAccent string `json:"theme_accent"`
+	ThemeInfo   string `json:"theme_info"`
+	ThemeOK     string `json:"theme_ok"`
+
+	// [audit]
+	AuditLog string `json:"audit_log"` // Append a JSONL record for every removed or skipped path to this file
+
+	// [log.terminal], [log.file], [log.syslog]
+	LogTerminalLevel string `json:"log_terminal_level"`
+	LogFile          string `json:"log_file"`
+	LogFileLevel     string `json:"log_file_level"`
+	Syslog           bool   `json:"syslog"`
+	SyslogNetwork    string `json:"syslog_network"` // unixgram or unix, empty tries both
+	SyslogAddress    string `json:"syslog_address"` // Default /dev/log
+	SyslogFacility   string `json:"syslog_facility"`
+	SyslogTag        string `json:"syslog_tag"`
+	SyslogLevel      string `json:"syslog_level"`
+
+	// [metrics]
+	MetricsTextfile string `json:"metrics_textfile"` // node_exporter textfile collector output
+
+	// [serve]
+	ServeToken string `json:"-"` // Bearer token for the HTTP API, never written to logs
+
+	// [webhooks.<name>], sorted by name
+	Webhooks []Webhook `json:"-"` // URLs often contain secrets
+
+	// [escalation]
+	EscalationOrder []string `json:"escalation_order"` // Programs from least to most valuable, used with -if-free-below
+
+	// [keys], action -> key names
+	Keys map[string][]string `json:"keys"`
+
+	// [presets.<name>]
+	Presets map[string][]string `json:"presets"`
+
+	// [catalog.<name>], sorted by name
+	Catalog []Program `json:"catalog"`
+}
+
+// cfg is the active configuration, filled by loadConfig
+var cfg = &Config{}
+
+// configDir returns the per-user config directory (~/.config/crunchycleaner or %APPDATA%\crunchycleaner)
+func configDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "crunchycleaner")
+}
+
+// stateDir returns the per-user directory for files CrunchyCleaner writes itself
+// (~/.local/state/crunchycleaner or %LOCALAPPDATA%\crunchycleaner)
+func stateDir() string {
+	if GOOS == "windows" {
+		if dir := os.Getenv("LOCALAPPDATA"); dir != "" {
+			return filepath.Join(dir, "crunchycleaner")
+		}
+	}
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "crunchycleaner")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local/state/crunchycleaner")
+}
+
+// loadConfig reads the config file. A missing file is not an error, the defaults are used instead.
+func loadConfig(path string) (*Config, error) {
+	c := &Config{Path: path, Presets: map[string][]string{}, Mouse: true}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+
+	tables, err := parseTOML(string(data))
+	if err != nil {
+		return c, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, t := range tables {
+		var errs []error
+		if len(t.Name) == 2 && t.Name[0] == "presets" {
+			var progs []string
+			if err := t.stringList("programs", &progs); err != nil {
+				return c, fmt.Errorf("%s: [presets.%s] %w", path, t.Name[1], err)
+			}
+			c.Presets[t.Name[1]] = progs
+			continue
+		}
+		if len(t.Name) == 2 && t.Name[0] == "catalog" {
+			p := Program{Name: t.Name[1], Category: "Custom"}
+			if err := errors.Join(t.stringList("paths", &p.Paths), t.str("category", &p.Category)); err != nil {
+				return c, fmt.Errorf("%s: [catalog.%s] %w", path, t.Name[1], err)
+			}
+			if len(p.Paths) == 0 {
+				return c, fmt.Errorf("%s: [catalog.%s] paths must not be empty", path, t.Name[1])
+			}
+			for _, pattern := range p.Paths {
+				if _, err := filepath.Match(pattern, ""); err != nil {
+					return c, fmt.Errorf("%s: [catalog.%s] %q: %w", path, t.Name[1], pattern, err)
+				}
+			}
+			c.Catalog = append(c.Catalog, p)
+			continue
+		}
+		if len(t.Name) == 2 && t.Name[0] == "webhooks" {
+			w, err := parseWebhook(t)
+			if err != nil {
+				return c, fmt.Errorf("%s: [webhooks.%s] %w", path, t.Name[1], err)
+			}
+			c.Webhooks = append(c.Webhooks, w)
+			continue
+		}
+		switch strings.Join(t.Name, ".") {
+		case "":
+		case "keys":
+			c.Keys = map[string][]string{}
+			for action := range t.Keys {
+				var keys []string
+				errs = append(errs, t.stringList(action, &keys))
+				c.Keys[action] = keys
+			}
+		case "defaults":
+			errs = append(errs, t.stringList("selected", &c.Selected), t.boolean("dry_run", &c.DryRun))
+		case "retention":
+			errs = append(errs, t.integer("min_age_days", &c.MinAgeDays), t.stringList("keep", &c.Keep))
+		case "audit":
+			errs = append(errs, t.str("path", &c.AuditLog))
+		case "log.terminal":
+			errs = append(errs, t.str("level", &c.LogTerminalLevel))
+		case "log.file":
+			errs = append(errs, t.str("path", &c.LogFile), t.str("level", &c.LogFileLevel))
+		case "log.syslog":
+			errs = append(errs,
+				t.boolean("enabled", &c.Syslog), t.str("network", &c.SyslogNetwork), t.str("address", &c.SyslogAddress),
+				t.str("facility", &c.SyslogFacility), t.str("tag", &c.SyslogTag), t.str("level", &c.SyslogLevel))
+		case "metrics":
+			errs = append(errs, t.str("textfile", &c.MetricsTextfile))
+		case "serve":
+			errs = append(errs, t.str("token", &c.ServeToken))
+		case "theme":
+			errs = append(errs, t.str("color", &c.Color), t.str("name", &c.Theme),
+				t.str("accent", &c.ThemeAccent), t.str("info", &c.ThemeInfo), t.str("ok", &c.ThemeOK))
+		case "escalation":
+			errs = append(errs, t.stringList("order", &c.EscalationOrder))
+		case "ui":
+			errs = append(errs, t.boolean("skip_init", &c.SkipInit), t.boolean("remember_last", &c.RememberLast), t.boolean("mouse", &c.Mouse),
+				t.str("language", &c.Language), t.boolean("accessible", &c.Accessible))
+		default:
+			errs = append(errs, fmt.Errorf("unknown section [%s]", strings.Join(t.Name, ".")))
+		}
+		if err := errors.Join(errs...); err != nil {
+			return c, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	sort.Slice(c.Webhooks, func(i, j int) bool { return c.Webhooks[i].Name < c.Webhooks[j].Name })
+	sort.Slice(c.Catalog, func(i, j int) bool { return c.Catalog[i].Name < c.Catalog[j].Name })
+	if c.MinAgeDays < 0 {
+		return c, fmt.Errorf("%s: min_age_days must not be negative", path)
+	}
+	return c, nil
+}
+
+// parseWebhook reads a [webhooks.<name>] table
+func parseWebhook(t *tomlTable) (Webhook, error) {
+	w := Webhook{Name: t.Name[1], Timeout: WEBHOOK_TIMEOUT, Retries: WEBHOOK_RETRIES}
+	var timeout, on string
+	if err := errors.Join(t.str("url", &w.URL), t.str("timeout", &timeout), t.integer("retries", &w.Retries), t.str("on", &on)); err != nil {
+		return w, err
+	}
+	if !strings.HasPrefix(w.URL, "http://") && !strings.HasPrefix(w.URL, "https://") {
+		return w, errors.New("url must start with http:// or https://")
+	}
+	if timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil || d <= 0 {
+			return w, fmt.Errorf("timeout: invalid duration %q", timeout)
+		}
+		w.Timeout = d
+	}
+	if w.Retries < 0 || w.Retries > WEBHOOK_MAX_RETRIES {
+		return w, fmt.Errorf("retries must be between 0 and %d", WEBHOOK_MAX_RETRIES)
+	}
+	switch on {
+	case "", "always":
+	case "failure":
+		w.OnFailure = true
+	default:
+		return w, fmt.Errorf("on: expected \"always\" or \"failure\", got %q", on)
+	}
+	return w, nil
+}
+
+// writeConfigTable replaces (or with an empty body removes) a table in the config file.
+// The rest of the file, including comments, is kept as is.
+func writeConfigTable(name []string, body string) error {
+	data, err := os.ReadFile(cfg.Path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	text := removeTOMLTable(string(data), name)
+	if body != "" {
+		if text != "" {
+			text += "\n"
+		}
+		keys := make([]string, len(name))
+		for i, k := range name {
+			keys[i] = tomlKey(k)
+		}
+		text += "[" + strings.Join(keys, ".") + "]\n" + body
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(cfg.Path, []byte(text), 0o644)
+}
+
+// writeCatalogEntry stores a custom catalog entry as a [catalog.<name>] table in the config file
+func writeCatalogEntry(p Program) error {
+	body := "paths = " + tomlStrings(p.Paths) + "\n"
+	if p.Category != "" && p.Category != "Custom" {
+		body += "category = " + tomlQuote(p.Category) + "\n"
+	}
+	return writeConfigTable([]string{"catalog", p.Name}, body)
+}
+
+// applyConfig copies config defaults onto flags the user didn't set explicitly
+func applyConfig(c *Config) {
+	set := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	if !set["d"] && c.DryRun {
+		*Flagdryrun = true
+	}
+	if !set["t"] && c.SkipInit {
+		*Flagnoinit = true
+	}
+	if !set["accessible"] && c.Accessible {
+		*Flagaccessible = true
+	}
+}
+
+// preselect checks every Program named in the config defaults or, if enabled, in the last run
+func preselect(programs []Program) {
+	checkNames(programs, cfg.Selected, false)
+	if cfg.RememberLast {
+		checkNames(programs, loadLastSelection(), false)
+	}
+}
+
+// retained reports whether the retention rules protect a file from deletion
+func retained(path string, info fs.FileInfo) bool {
+	return retainReason(path, info) != ""
+}
+
+// retainReason explains which retention rule protects a file, or returns "" if none does
+func retainReason(path string, info fs.FileInfo) string {
+	if cfg.MinAgeDays > 0 && time.Since(info.ModTime()) < time.Duration(cfg.MinAgeDays)*24*time.Hour {
+		return fmt.Sprintf("retention: newer than %d days", cfg.MinAgeDays)
+	}
+	for _, pattern := range cfg.Keep {
+		if ok, _ := filepath.Match(pattern, filepath.Base(path)); ok {
+			return "retention: matches keep pattern " + pattern
+		}
+	}
+	return ""
+}
+
+// hasRetention reports whether any retention rule is configured
+func hasRetention() bool {
+	return cfg.MinAgeDays > 0 || len(cfg.Keep) > 0
+}
+
+// ========================= LAST SELECTION =========================
+
+func lastSelectionFile() string {
+	return filepath.Join(stateDir(), "last_selection")
+}
+
+// loadLastSelection returns the Program names checked in the previous run
+func loadLastSelection() []string {
+	data, err := os.ReadFile(lastSelectionFile())
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, l := range strings.Split(string(data), "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			names = append(names, l)
+		}
+	}
+	return names
+}
+
+// saveLastSelection stores the checked Program names, one per line
+func saveLastSelection(programs []Program) error {
+	var b strings.Builder
+	for _, p := range programs {
+		if p.Checked {
+			b.WriteString(p.Name + "\n")
+		}
+	}
+	if err := os.MkdirAll(stateDir(), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(lastSelectionFile(), []byte(b.String()), 0o644)
+}
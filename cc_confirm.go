@@ -0,0 +1,176 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eiannone/keyboard"
+)
+
+// appProcesses lists the processes that keep a Program's cache open while running.
+// Linux cuts process names to 15 characters, so "chromium" also matches "chromium-browse".
+var appProcesses = map[string][]string{
+	"Firefox Cache":                    {"firefox"},
+	"Chrome Cache":                     {"chrome"},
+	"Chromium Cache":                   {"chromium", "chrome"},
+	"Edge Cache":                       {"msedge", "microsoft-edge"},
+	"Brave Cache":                      {"brave"},
+	"Opera Cache":                      {"opera"},
+	"Thunderbird Cache":                {"thunderbird"},
+	"Steam Cache":                      {"steam"},
+	"Epic Games Cache":                 {"epicgameslauncher"},
+	"Epic Games (Heroic/Lutris) Cache": {"heroic", "lutris"},
+	"Discord Cache":                    {"discord"},
+	"Telegram Cache":                   {"telegram", "telegram-desktop"},
+	"Spotify Cache":                    {"spotify"},
+	"VS Code Cache":                    {"code"},
+}
+
+// cleanupSummary is what the confirmation screen shows about the checked Programs
+type cleanupSummary struct {
+	programs     []Program
+	paths, files int
+	bytes        int64
+	mounts       []*Mount // Freed holds the bytes expected to be freed
+	warnings     []string
+}
+
+// summarize collects the scan data of the checked Programs, per mount and with warnings
+func summarize(programs []Program) cleanupSummary {
+	var s cleanupSummary
+	mounts := map[string]*Mount{}
+	procs := runningProcesses()
+	for _, p := range programs {
+		if !p.Checked {
+			continue
+		}
+		s.programs = append(s.programs, p)
+		s.bytes += p.Size
+		for _, m := range p.Matches {
+			s.paths++
+			s.files += m.Files
+			mnt, err := mountOf(m.Path)
+			if err != nil {
+				continue
+			}
+			if mounts[mnt] == nil {
+				mounts[mnt] = &Mount{Path: mnt}
+				mounts[mnt].Total, mounts[mnt].Free, _ = diskUsage(mnt)
+				s.mounts = append(s.mounts, mounts[mnt])
+			}
+			mounts[mnt].Freed += uint64(m.Size)
+		}
+
+		if (strings.Contains(p.Name, "(Root)") || strings.Contains(p.Name, "(Admin)")) && !isPrivileged() {
+			s.warnings = append(s.warnings, tr("confirm.needs_rights", p.Name))
+		}
+		for _, name := range appProcesses[p.Name] {
+			if processRunning(procs, name) {
+				s.warnings = append(s.warnings, tr("confirm.running", p.Name, name))
+				break
+			}
+		}
+	}
+	sort.Slice(s.mounts, func(i, j int) bool { return s.mounts[i].Path < s.mounts[j].Path })
+	if freeThreshold != nil {
+		s.warnings = append(s.warnings, tr("confirm.threshold", freeThreshold))
+	}
+	return s
+}
+
+// processRunning reports whether a process called name, or name followed by a separator, is running
+func processRunning(procs map[string]bool, name string) bool {
+	for p := range procs {
+		if p == name || strings.HasPrefix(p, name) && strings.ContainsRune("-_. ", rune(p[len(name)])) {
+			return true
+		}
+	}
+	return false
+}
+
+// confirmLines renders the confirmation screen into at most rows lines, shortening the Program list if needed
+func confirmLines(s cleanupSummary, rows int) []string {
+	head := []string{CYAN + tr("confirm.title") + RC, separator()}
+
+	var tail []string
+	tail = append(tail, separator(), tr("confirm.total",
+		len(s.programs), s.paths, s.files, YELLOW+formatMB(s.bytes)+RC))
+	for _, m := range s.mounts {
+		tail = append(tail, fmt.Sprintf("  %-12s %s%10s%s  %s",
+			m.Path, YELLOW, formatMB(int64(m.Freed)), RC, tr("confirm.mount", float64(m.Free)/(1<<30), float64(m.Total)/(1<<30))))
+	}
+	for _, w := range s.warnings {
+		tail = append(tail, YELLOW+"[!] "+w+RC)
+	}
+	mode := YELLOW + tr("confirm.delete") + RC
+	if *Flagdryrun {
+		mode = GREEN + tr("confirm.dry") + RC
+	}
+	tail = append(tail, separator(), tr("confirm.mode", mode), tr("confirm.keys"))
+
+	// Keep one spare row so the last newline doesn't scroll
+	room := max(rows-len(head)-len(tail)-1, 1)
+	var list []string
+	for i, p := range s.programs {
+		if len(list) == room-1 && i < len(s.programs)-1 {
+			list = append(list, "  "+tr("confirm.more", len(s.programs)-i))
+			break
+		}
+		list = append(list, fmt.Sprintf("  %-30s %s  %s%10s%s", p.Name, tr("confirm.row", len(p.Matches), p.files()), YELLOW, formatMB(p.Size), RC))
+	}
+	return append(append(head, list...), tail...)
+}
+
+// confirmCleanup shows what a cleanup of the checked Programs would delete and waits for the user's decision.
+// The dry run setting can be toggled from here. It returns false when the user goes back to the menu.
+func confirmCleanup(programs []Program, keys <-chan inputEvent, resized <-chan struct{}) bool {
+	s := summarize(programs)
+	draw := func() {
+		cols, rows := screenSize()
+		fmt.Print(CLEAR_SCREEN)
+		for _, l := range confirmLines(s, rows) {
+			fmt.Printf("%s\n", fitWidth(l, cols))
+		}
+	}
+	draw()
+	for {
+		select {
+		case ev, ok := <-keys:
+			switch {
+			case !ok || ev.Err != nil:
+				return false
+			case ev.Rune == 'y' || ev.Rune == 'Y':
+				return true
+			case ev.Rune == 'n' || ev.Rune == 'N' || ev.Key == keyboard.KeyEsc:
+				return false
+			case ev.Rune == 'd' || ev.Rune == 'D':
+				*Flagdryrun = !*Flagdryrun
+				draw()
+			case ev.Key == keyboard.KeyCtrlC:
+				cc_exit(EXIT_ABORTED)
+			}
+		case <-resized:
+			draw()
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FreeThreshold is the value of -if-free-below: either an absolute size or a percentage of the mount size
+type FreeThreshold struct {
+	Bytes   uint64
+	Percent float64
+}
+
+// freeThreshold is set from -if-free-below, nil means always clean
+var freeThreshold *FreeThreshold
+
+// parseSize parses sizes like "512MB", "10GB", "1.5T" or "2048" (bytes), using 1024-based units
+func parseSize(s string) (uint64, error) {
+	units := []struct {
+		suffix string
+		mult   float64
+	}{
+		{"TIB", 1 << 40}, {"GIB", 1 << 30}, {"MIB", 1 << 20}, {"KIB", 1 << 10},
+		{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+		{"T", 1 << 40}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+	}
+	num, mult := strings.ToUpper(strings.TrimSpace(s)), 1.0
+	for _, u := range units {
+		if strings.HasSuffix(num, u.suffix) {
+			num, mult = strings.TrimSpace(strings.TrimSuffix(num, u.suffix)), u.mult
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return uint64(v * mult), nil
+}
+
+// parseFreeThreshold parses "10GB" or "15%"
+func parseFreeThreshold(s string) (*FreeThreshold, error) {
+	if pct, ok := strings.CutSuffix(strings.TrimSpace(s), "%"); ok {
+		v, err := strconv.ParseFloat(strings.TrimSpace(pct), 64)
+		if err != nil || v <= 0 || v > 100 {
+			return nil, fmt.Errorf("invalid percentage %q", s)
+		}
+		return &FreeThreshold{Percent: v}, nil
+	}
+	b, err := parseSize(s)
+	if err != nil {
+		return nil, err
+	}
+	return &FreeThreshold{Bytes: b}, nil
+}
+
+func (t *FreeThreshold) String() string {
+	if t.Percent > 0 {
+		return fmt.Sprintf("%g%%", t.Percent)
+	}
+	if t.Bytes < 1<<30 {
+		return formatMB(int64(t.Bytes))
+	}
+	return fmt.Sprintf("%.2f GB", float64(t.Bytes)/(1<<30))
+}
+
+// below reports whether free space on a mount of the given size is under the threshold
+func (t *FreeThreshold) below(total, free uint64) bool {
+	if t.Percent > 0 {
+		return total > 0 && float64(free)/float64(total)*100 < t.Percent
+	}
+	return free < t.Bytes
+}
+
+// Mount is a filesystem holding some of the selected caches
+type Mount struct {
+	Path        string
+	Total, Free uint64
+	Freed       uint64 // Bytes cleaned on this mount so far (used to simulate dry runs)
+}
+
+// escalationOrder lists Programs from least to most valuable, i.e. cheapest to rebuild first.
+// Programs that are not listed come last, in catalog order. [escalation] order in the config replaces it.
+var escalationOrder = []string{
+	"System Temp Folders (Root)", "System Temp Folders (Admin)", "User Temp Folder",
+	"Thumbnail Cache", "Font Cache (Admin)", "Shader Cache",
+	"Firefox Cache", "Chrome Cache", "Chromium Cache", "Edge Cache", "Brave Cache", "Opera Cache",
+	"Thunderbird Cache", "Discord Cache", "Telegram Cache", "Spotify Cache",
+	"Steam Cache", "Epic Games Cache", "Epic Games (Heroic/Lutris) Cache",
+	"System Logs (Root)", "System Logs (Admin)", "Update Logs (Admin)",
+	"Go Build Cache", "Pip Cache", "NPM Cache", "Yarn Cache", "Cargo Cache",
+	"VS Code Cache",
+}
+
+// programMounts returns the mount points of all paths matched by a scanned Program
+func programMounts(p Program) []string {
+	seen := map[string]bool{}
+	var mounts []string
+	for _, m := range p.Matches {
+		if mnt, err := mountOf(m.Path); err == nil && !seen[mnt] {
+			seen[mnt] = true
+			mounts = append(mounts, mnt)
+		}
+	}
+	return mounts
+}
+
+// pressuredMounts returns the mounts of checked Programs whose free space is below the threshold
+func pressuredMounts(programs []Program, t *FreeThreshold) map[string]*Mount {
+	pressure := map[string]*Mount{}
+	checked := map[string]bool{}
+	for _, p := range programs {
+		if !p.Checked {
+			continue
+		}
+		for _, mnt := range programMounts(p) {
+			if checked[mnt] {
+				continue
+			}
+			checked[mnt] = true
+			total, free, err := diskUsage(mnt)
+			if err == nil && t.below(total, free) {
+				pressure[mnt] = &Mount{Path: mnt, Total: total, Free: free}
+			}
+		}
+	}
+	return pressure
+}
+
+// escalate returns the checked Programs with data on a pressured mount, least valuable first.
+// Their matches are narrowed to the ones on a pressured mount (mountOf goes by the device ID),
+// so caches on filesystems with enough free space are left alone.
+func escalate(programs []Program, pressure map[string]*Mount) []Program {
+	order := escalationOrder
+	if len(cfg.EscalationOrder) > 0 {
+		order = cfg.EscalationOrder
+	}
+	rank := map[string]int{}
+	for i, n := range order {
+		rank[strings.ToLower(n)] = i + 1
+	}
+
+	var out []Program
+	for _, p := range programs {
+		if !p.Checked {
+			continue
+		}
+		var matches []PathInfo
+		var size int64
+		for _, m := range p.Matches {
+			if mnt, err := mountOf(m.Path); err == nil && pressure[mnt] != nil {
+				matches = append(matches, m)
+				size += m.Size
+			}
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		p.Paths = make([]string, len(matches))
+		for i, m := range matches {
+			p.Paths[i] = globEscape(m.Path)
+		}
+		p.Matches, p.Size = matches, size
+		out = append(out, p)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		ri, rj := rank[strings.ToLower(out[i].Name)], rank[strings.ToLower(out[j].Name)]
+		if ri == 0 || rj == 0 {
+			return ri != 0 && rj == 0
+		}
+		return ri < rj
+	})
+	return out
+}
+
+// thresholdMet reports whether every pressured mount is above the threshold again.
+// In dry runs nothing is deleted, so the bytes that would have been freed are added instead.
+func thresholdMet(pressure map[string]*Mount, t *FreeThreshold, dryRun bool) bool {
+	for _, m := range pressure {
+		free := m.Free + m.Freed
+		if !dryRun {
+			if _, f, err := diskUsage(m.Path); err == nil {
+				free = f
+			}
+		}
+		if t.below(m.Total, free) {
+			return false
+		}
+	}
+	return true
+}
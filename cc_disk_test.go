@@ -0,0 +1,56 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestEscalateKeepsPressuredMount(t *testing.T) {
+	low := t.TempDir()
+	other, err := os.MkdirTemp("/dev/shm", "crunchycleaner-test-")
+	if err != nil {
+		t.Skip("no second filesystem:", err)
+	}
+	defer os.RemoveAll(other)
+	lowMnt, err1 := mountOf(low)
+	otherMnt, err2 := mountOf(other)
+	if err1 != nil || err2 != nil || lowMnt == otherMnt {
+		t.Skipf("%s and %s are on the same filesystem", low, other)
+	}
+
+	programs := []Program{
+		{Name: "Both", Checked: true, Paths: []string{low + "/*", other + "/*"},
+			Matches: []PathInfo{{Path: low, Size: 10}, {Path: other, Size: 20}}},
+		{Name: "Elsewhere", Checked: true, Paths: []string{other}, Matches: []PathInfo{{Path: other, Size: 5}}},
+		{Name: "Unchecked", Paths: []string{low}, Matches: []PathInfo{{Path: low, Size: 1}}},
+	}
+	got := escalate(programs, map[string]*Mount{lowMnt: {Path: lowMnt}})
+	want := []Program{{Name: "Both", Checked: true, Paths: []string{globEscape(low)},
+		Matches: []PathInfo{{Path: low, Size: 10}}, Size: 10}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("escalate = %+v, want %+v", got, want)
+	}
+	if len(programs[0].Matches) != 2 {
+		t.Errorf("escalate changed the Matches of its input")
+	}
+}
@@ -0,0 +1,74 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// diskUsage returns the total and available bytes of the filesystem holding path
+func diskUsage(path string) (total, free uint64, err error) {
+	blocks, avail, bsize, err := statfs(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	return blocks * bsize, avail * bsize, nil
+}
+
+// mountOf returns the mount point of the filesystem holding path,
+// found by walking up until the device number changes
+func mountOf(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	var st syscall.Stat_t
+	if err := syscall.Stat(path, &st); err != nil {
+		return "", err
+	}
+	for {
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path, nil
+		}
+		var pst syscall.Stat_t
+		if err := syscall.Stat(parent, &pst); err != nil || pst.Dev != st.Dev {
+			return path, nil
+		}
+		path = parent
+	}
+}
+
+// systemRoot is the mount shown in the banner
+func systemRoot() string {
+	return "/"
+}
+
+// fileOwner returns the owner UID of a file
+func fileOwner(info os.FileInfo) (int, bool) {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int(st.Uid), true
+	}
+	return 0, false
+}
@@ -0,0 +1,61 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/windows"
+)
+
+// diskUsage returns the total and available bytes of the volume holding path
+func diskUsage(path string) (total, free uint64, err error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	err = windows.GetDiskFreeSpaceEx(p, &free, &total, nil)
+	return total, free, err
+}
+
+// mountOf returns the volume (e.g. C:\) holding path
+func mountOf(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.VolumeName(path) + "\\", nil
+}
+
+// systemRoot is the drive shown in the banner
+func systemRoot() string {
+	if drive := os.Getenv("SystemDrive"); drive != "" {
+		return drive + "\\"
+	}
+	return "C:\\"
+}
+
+// fileOwner is not supported on Windows, files have SIDs instead of UIDs
+func fileOwner(info os.FileInfo) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,356 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eiannone/keyboard"
+)
+
+// explorer is an ncdu-like view of any directory, to find out where space goes
+type explorer struct {
+	root    string
+	dir     string
+	entries []browseEntry
+	idx     int
+	view    viewport
+	cache   map[string][]browseEntry
+	catalog map[string]string // Path -> name of the catalog entry matching it
+	status  string            // Message for the status line, cleared by the next key
+	failed  bool              // A deletion had errors
+}
+
+// load lists dir, showing a spinner while it is sized for the first time
+func (x *explorer) load(dir string) {
+	x.dir, x.idx, x.view.top = dir, 0, 0
+	if cached, ok := x.cache[dir]; ok {
+		x.entries = cached
+		return
+	}
+	fmt.Print(CLEAR_SCREEN)
+	stop := make(chan bool)
+	ack := make(chan bool)
+	go spinner(tr("explore.sizing", dir), stop, ack)
+	x.entries = listDir(dir)
+	stop <- true
+	<-ack
+	x.cache[dir] = x.entries
+}
+
+// reload drops the cached sizes of dir and everything above it, then lists dir again
+func (x *explorer) reload() {
+	idx := x.idx
+	for d := x.dir; ; d = filepath.Dir(d) {
+		delete(x.cache, d)
+		if d == x.root || d == filepath.Dir(d) {
+			break
+		}
+	}
+	x.load(x.dir)
+	x.idx = min(idx, max(len(x.entries)-1, 0))
+}
+
+// loadCatalog remembers which paths the catalog already covers
+func (x *explorer) loadCatalog() {
+	x.catalog = map[string]string{}
+	for _, p := range getPrograms() {
+		for _, pattern := range p.Paths {
+			matches, _ := filepath.Glob(expandHome(pattern))
+			for _, m := range matches {
+				x.catalog[m] = p.Name
+			}
+		}
+	}
+}
+
+func (x *explorer) current() *browseEntry {
+	if x.idx < len(x.entries) {
+		return &x.entries[x.idx]
+	}
+	return nil
+}
+
+// render draws the explorer over the whole screen
+func (x *explorer) render() {
+	cols, rows := screenSize()
+	var total int64
+	for _, e := range x.entries {
+		total += e.Size
+	}
+	fmt.Print(CLEAR_SCREEN)
+	fmt.Printf("%s\n", fitWidth(fmt.Sprintf("%s%s%s > %s (%s)", CYAN, tr("explore.title"), RC, x.dir, formatMB(total)), cols))
+	fmt.Printf("%s\n", fitWidth(tr("explore.keys", bindings.label(ACT_BROWSE), bindings.label(ACT_PARENT), bindings.label(ACT_DELETE),
+		bindings.label(ACT_CATALOG), bindings.label(ACT_CLOSE)), cols))
+
+	// Two header lines, the status line and a spare row
+	x.view.height = max(rows-4, 1)
+	x.view.follow(x.idx, len(x.entries))
+	largest := largestEntry(x.entries)
+	if len(x.entries) == 0 {
+		fmt.Printf("    %s\n", tr("list.empty"))
+	}
+	for i := x.view.top; i < x.view.end(len(x.entries)); i++ {
+		e := x.entries[i]
+		name := filepath.Base(e.Path)
+		if c, ok := x.catalog[e.Path]; ok {
+			name += CYAN + "  (" + c + ")" + RC
+		}
+		fmt.Printf("%s\n", fitWidth(sizeRow(i == x.idx, "", e, largest, name), cols))
+	}
+
+	status := x.status
+	if status == "" {
+		status = tr("explore.entries", len(x.entries))
+		if x.view.height < len(x.entries) {
+			status = tr("explore.range", x.view.top+1, x.view.end(len(x.entries)), len(x.entries))
+		}
+		if *Flagdryrun {
+			status += " | " + YELLOW + tr("explore.dry") + RC
+		}
+	}
+	fmt.Printf("%s", fitWidth(status, cols))
+}
+
+// prompt shows question in the status line and lets the user type an answer.
+// ok is false if the prompt was cancelled with ESC.
+func (x *explorer) prompt(question, answer string, keys <-chan inputEvent, resized <-chan struct{}) (string, bool) {
+	for {
+		x.status = question + answer + "_"
+		x.render()
+		var ev inputEvent
+		var ok bool
+		select {
+		case ev, ok = <-keys:
+		case <-resized:
+			continue
+		}
+		switch {
+		case !ok || ev.Err != nil || ev.Key == keyboard.KeyEsc:
+			return "", false
+		case ev.Key == keyboard.KeyCtrlC:
+			cc_exit(EXIT_ABORTED)
+		case ev.Key == keyboard.KeyEnter:
+			return strings.TrimSpace(answer), true
+		case ev.Key == keyboard.KeyBackspace || ev.Key == keyboard.KeyBackspace2:
+			if r := []rune(answer); len(r) > 0 {
+				answer = string(r[:len(r)-1])
+			}
+		case ev.Key == keyboard.KeySpace:
+			answer += " "
+		case ev.Rune != 0:
+			answer += string(ev.Rune)
+		}
+	}
+}
+
+// confirm asks a yes/no question in the status line
+func (x *explorer) confirm(question string, keys <-chan inputEvent, resized <-chan struct{}) bool {
+	x.status = question + " " + tr("explore.yes_no")
+	x.render()
+	for {
+		select {
+		case ev, ok := <-keys:
+			switch {
+			case ev.Rune == 'y' || ev.Rune == 'Y':
+				return true
+			case ev.Key == keyboard.KeyCtrlC:
+				cc_exit(EXIT_ABORTED)
+			case !ok || ev.Err != nil || ev.Rune == 'n' || ev.Rune == 'N' || ev.Key == keyboard.KeyEsc:
+				return false
+			}
+		case <-resized:
+			x.render()
+		}
+	}
+}
+
+// delete removes e through the same path as a cleanup, so retention rules and the audit log apply.
+// Folders emptied that way are removed as well.
+func (x *explorer) delete(e browseEntry) {
+	if *Flagdryrun {
+		logInfo(tr("explore.would", e.Path))
+		x.status = tr("explore.dry_status", e.Path)
+		return
+	}
+	if auditLog != nil {
+		auditLog.startSession(false)
+		auditLog.program = "explore"
+	}
+	errs := deletePath(e.Path, nil, nil)
+	if e.dir {
+		if info, err := os.Lstat(e.Path); err == nil && info.IsDir() && os.Remove(e.Path) == nil {
+			audit("removed", e.Path, info, "empty after cleanup")
+		}
+	}
+	if len(errs) > 0 {
+		x.failed = true
+		x.status = YELLOW + tr("explore.deleted_errors", e.Path, len(errs)) + RC
+	} else {
+		logQuiet(LOG_OK, tr("explore.deleted", e.Path))
+		x.status = GREEN + tr("explore.deleted_size", e.Path, formatMB(e.Size)) + RC
+	}
+	x.reload()
+}
+
+// addToCatalog stores e as a custom catalog entry, so later runs offer to clean it
+func (x *explorer) addToCatalog(e browseEntry, keys <-chan inputEvent, resized <-chan struct{}) {
+	if c, ok := x.catalog[e.Path]; ok {
+		x.status = tr("explore.in_catalog", c)
+		return
+	}
+	name, ok := x.prompt(tr("explore.name")+" ", filepath.Base(e.Path), keys, resized)
+	if !ok || name == "" {
+		x.status = ""
+		return
+	}
+	for _, p := range getPrograms() {
+		if strings.EqualFold(p.Name, name) {
+			x.status = YELLOW + tr("explore.taken", name) + RC
+			return
+		}
+	}
+	p := Program{Name: name, Category: "Custom", Paths: []string{catalogPath(e.Path)}}
+	if err := writeCatalogEntry(p); err != nil {
+		x.status = YELLOW + tr("explore.save_error", err) + RC
+		return
+	}
+	cfg.Catalog = append(cfg.Catalog, p)
+	x.catalog[e.Path] = name
+	x.status = GREEN + tr("explore.added", name, cfg.Path) + RC
+}
+
+// catalogPath turns a path into a catalog pattern that matches only that path
+func catalogPath(path string) string {
+	return globEscape(homePath(path))
+}
+
+// homePath abbreviates paths inside the home directory to '~/...', the reverse of expandHome
+func homePath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		return "~/" + filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// exploreCommand implements 'crunchycleaner explore [dir]'
+func exploreCommand(args []string) int {
+	if len(args) > 1 {
+		fmt.Fprintf(os.Stderr, "Usage:\n  crunchycleaner explore [dir]\n")
+		return EXIT_USAGE
+	}
+	root := "."
+	if len(args) == 1 {
+		root = expandHome(args[0])
+	}
+	root, err := filepath.Abs(root)
+	if err == nil {
+		var info os.FileInfo
+		if info, err = os.Stat(root); err == nil && !info.IsDir() {
+			err = fmt.Errorf("%s is not a directory", root)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot explore: %v\n", err)
+		return EXIT_USAGE
+	}
+
+	keys, err := openInput()
+	if err != nil {
+		panic(err)
+	}
+	defer closeInput()
+	resized := watchResize()
+
+	x := &explorer{root: root, cache: map[string][]browseEntry{}}
+	x.loadCatalog()
+	x.load(root)
+	x.render()
+	for {
+		var ev inputEvent
+		var ok bool
+		select {
+		case ev, ok = <-keys:
+		case <-resized:
+			x.render()
+			continue
+		}
+		if !ok || ev.Err != nil {
+			break
+		}
+		x.status = ""
+		if idx, ok := moveCursor(ev, x.idx, len(x.entries), x.view.height); ok {
+			x.idx = idx
+			x.render()
+			continue
+		}
+		switch action := bindings.actionIn(SCOPE_EXPLORE, ev); {
+		case action == ACT_BROWSE || action == ACT_TOGGLE:
+			if e := x.current(); e != nil && e.dir {
+				x.load(e.Path)
+			}
+		case action == ACT_PARENT:
+			if x.dir == x.root {
+				continue
+			}
+			from := x.dir
+			x.load(filepath.Dir(x.dir))
+			for i, e := range x.entries {
+				if e.Path == from {
+					x.idx = i
+				}
+			}
+		case action == ACT_DELETE:
+			if e := x.current(); e != nil {
+				if x.confirm(YELLOW+tr("explore.confirm", e.Path, formatMB(e.Size))+RC, keys, resized) {
+					x.delete(*e)
+				} else {
+					x.status = ""
+				}
+			}
+		case action == ACT_CATALOG:
+			if e := x.current(); e != nil {
+				x.addToCatalog(*e, keys, resized)
+			}
+		case ev.Key == keyboard.KeyEsc || action == ACT_CLOSE:
+			fmt.Print(CLEAR_SCREEN)
+			if x.failed {
+				return EXIT_PARTIAL
+			}
+			return EXIT_OK
+		case ev.Key == keyboard.KeyCtrlC:
+			return EXIT_ABORTED
+		default:
+			continue
+		}
+		x.render()
+	}
+	if x.failed {
+		return EXIT_PARTIAL
+	}
+	return EXIT_OK
+}
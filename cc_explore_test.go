@@ -0,0 +1,70 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Deleting a symlink to a folder, as 'd' in the explorer does, removes the link and leaves the folder alone
+func TestDeleteSymlinkToDir(t *testing.T) {
+	for _, name := range []string{"fast", "walk"} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			target := filepath.Join(dir, "outside")
+			os.Mkdir(target, 0o755)
+			os.WriteFile(filepath.Join(target, "file"), []byte("data"), 0o644)
+			link := filepath.Join(dir, "explored", "link")
+			os.Mkdir(filepath.Dir(link), 0o755)
+			if err := os.Symlink(target, link); err != nil {
+				t.Skip("symlinks not supported:", err)
+			}
+
+			var removed func(int64)
+			if name == "walk" {
+				removed = func(int64) {}
+			}
+			if errs := deletePath(link, nil, removed); len(errs) > 0 {
+				t.Fatalf("deletePath: %v", errs)
+			}
+			if _, err := os.Lstat(link); !os.IsNotExist(err) {
+				t.Errorf("symlink still there: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(target, "file")); err != nil {
+				t.Errorf("target of the symlink was emptied: %v", err)
+			}
+		})
+	}
+}
+
+func TestCatalogPath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cache [v2]*?")
+	os.Mkdir(path, 0o755)
+	os.Mkdir(filepath.Join(dir, "cache v"), 0o755)
+
+	pattern := catalogPath(path)
+	matches, err := filepath.Glob(expandHome(pattern))
+	if err != nil || len(matches) != 1 || matches[0] != path {
+		t.Errorf("pattern %q matches %q (%v), want only %q", pattern, matches, err, path)
+	}
+}
@@ -0,0 +1,277 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HistoryEntry is one cleanup session as stored in history.jsonl
+type HistoryEntry struct {
+	Time      time.Time        `json:"time"`
+	User      string           `json:"user"`
+	DryRun    bool             `json:"dry_run"`
+	Cancelled bool             `json:"cancelled,omitempty"` // Interrupted, the rest may have been finished by 'resume'
+	Duration  float64          `json:"duration_seconds"`
+	Programs  []HistoryProgram `json:"programs"`
+	Errors    []string         `json:"errors,omitempty"`
+}
+
+// HistoryProgram is the outcome for a single Program within a session
+type HistoryProgram struct {
+	Name   string `json:"name"`
+	Bytes  int64  `json:"bytes"`
+	Paths  int    `json:"paths"`
+	Errors int    `json:"errors"`
+}
+
+func historyFile() string {
+	return filepath.Join(stateDir(), "history.jsonl")
+}
+
+// newHistoryEntry converts a cleanup result into its stored (and JSON API) form
+func newHistoryEntry(r *CleanResult) HistoryEntry {
+	e := HistoryEntry{
+		Time:      r.Start,
+		User:      currentUsername(),
+		DryRun:    r.DryRun,
+		Cancelled: r.Cancelled,
+		Duration:  r.Duration.Seconds(),
+	}
+	for _, p := range r.Programs {
+		e.Programs = append(e.Programs, HistoryProgram{p.Name, p.Bytes, p.Paths, len(p.Errors)})
+		for _, err := range p.Errors {
+			e.Errors = append(e.Errors, err.Error())
+		}
+	}
+	return e
+}
+
+// recordHistory appends a finished session to the history file
+func recordHistory(r *CleanResult) error {
+	if r.NotNeeded || len(r.Programs) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(newHistoryEntry(r))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(stateDir(), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(historyFile(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// loadHistory reads every session, skipping lines that can't be parsed
+func loadHistory() ([]HistoryEntry, error) {
+	f, err := os.Open(historyFile())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var e HistoryEntry
+		if json.Unmarshal(sc.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	return entries, sc.Err()
+}
+
+// ========================= HISTORY COMMAND =========================
+
+// historyCommand implements 'crunchycleaner history'
+func historyCommand(args []string) int {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	by := fs.String("by", "month", "Group the trend by day, week or month")
+	last := fs.Int("n", 10, "Number of recent sessions to list")
+	dry := fs.Bool("include-dry-runs", false, "Count dry runs in the statistics")
+	if err := fs.Parse(args); err != nil {
+		return EXIT_USAGE
+	}
+	if *by != "day" && *by != "week" && *by != "month" {
+		fmt.Fprintf(os.Stderr, "history: -by must be day, week or month\n")
+		return EXIT_USAGE
+	}
+	if *last < 0 {
+		fmt.Fprintf(os.Stderr, "history: -n must not be negative\n")
+		return EXIT_USAGE
+	}
+
+	all, err := loadHistory()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "history: %v\n", err)
+		return EXIT_PARTIAL
+	}
+	var entries []HistoryEntry
+	for _, e := range all {
+		if !e.DryRun || *dry {
+			entries = append(entries, e)
+		}
+	}
+	if len(entries) == 0 {
+		fmt.Println(tr("history.none"))
+		return EXIT_OK
+	}
+
+	// Totals
+	var total int64
+	var errCount int
+	for _, e := range entries {
+		for _, p := range e.Programs {
+			total += p.Bytes
+		}
+		errCount += len(e.Errors)
+	}
+	fmt.Printf("%s%s%s\n", YELLOW, tr("history.totals"), RC)
+	fmt.Printf("  %s\n", tr("history.sessions", len(entries),
+		entries[0].Time.Format("2006-01-02"), entries[len(entries)-1].Time.Format("2006-01-02")))
+	fmt.Printf("  %s\n", tr("history.reclaimed", YELLOW+formatMB(total)+RC))
+	fmt.Printf("  %s\n", tr("history.errors", errCount))
+
+	// Recent sessions
+	fmt.Printf("\n%s%s%s\n", YELLOW, tr("history.recent"), RC)
+	for _, e := range entries[max(0, len(entries)-*last):] {
+		var bytes int64
+		for _, p := range e.Programs {
+			bytes += p.Bytes
+		}
+		dryMark := ""
+		if e.DryRun {
+			dryMark = " " + tr("history.dry_run")
+		}
+		fmt.Printf("  %s  %-10s %12s  %s  %s%s\n", e.Time.Format("2006-01-02 15:04"), e.User, formatMB(bytes),
+			tr("history.caches", len(e.Programs)), tr("history.session_errors", len(e.Errors)), dryMark)
+	}
+
+	printTrend(entries, *by)
+	printRegrowth(entries)
+	return EXIT_OK
+}
+
+// periodKey groups a timestamp by day, ISO week or month
+func periodKey(t time.Time, by string) string {
+	switch by {
+	case "day":
+		return t.Format("2006-01-02")
+	case "week":
+		y, w := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", y, w)
+	}
+	return t.Format("2006-01")
+}
+
+// printTrend shows the reclaimed bytes per period as a small bar chart
+func printTrend(entries []HistoryEntry, by string) {
+	sums := map[string]int64{}
+	var keys []string
+	for _, e := range entries {
+		k := periodKey(e.Time, by)
+		if _, ok := sums[k]; !ok {
+			keys = append(keys, k)
+		}
+		for _, p := range e.Programs {
+			sums[k] += p.Bytes
+		}
+	}
+	var peak int64 = 1
+	for _, v := range sums {
+		peak = max(peak, v)
+	}
+
+	fmt.Printf("\n%s%s%s\n", YELLOW, tr("history.trend", tr("history."+by)), RC)
+	const width = 30
+	for _, k := range keys {
+		bar := int(sums[k] * width / peak)
+		fmt.Printf("  %-10s %12s %s%s%s\n", k, formatMB(sums[k]), GREEN, strings.Repeat("#", bar), RC)
+	}
+}
+
+// printRegrowth estimates how fast each cache grows back: the bytes found at a cleanup
+// divided by the time since the previous cleanup of the same Program. Dry runs are skipped
+// even with -include-dry-runs, they leave the cache in place.
+func printRegrowth(entries []HistoryEntry) {
+	type growth struct {
+		name    string
+		perDay  float64
+		samples int
+	}
+	lastClean := map[string]time.Time{}
+	rates := map[string]*growth{}
+	for _, e := range entries {
+		if e.DryRun {
+			continue
+		}
+		for _, p := range e.Programs {
+			if prev, ok := lastClean[p.Name]; ok {
+				days := e.Time.Sub(prev).Hours() / 24
+				if days > 0 {
+					g := rates[p.Name]
+					if g == nil {
+						g = &growth{name: p.Name}
+						rates[p.Name] = g
+					}
+					// Running average over all intervals
+					g.perDay += (float64(p.Bytes)/days - g.perDay) / float64(g.samples+1)
+					g.samples++
+				}
+			}
+			lastClean[p.Name] = e.Time
+		}
+	}
+
+	fmt.Printf("\n%s%s%s\n", YELLOW, tr("history.growth"), RC)
+	if len(rates) == 0 {
+		fmt.Println("  " + tr("history.growth_none"))
+		return
+	}
+	list := make([]*growth, 0, len(rates))
+	for _, g := range rates {
+		list = append(list, g)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].perDay > list[j].perDay })
+	for _, g := range list[:min(len(list), 10)] {
+		fmt.Printf("  %-32s %s\n", g.name, tr("history.growth_rate", fmt.Sprintf("%12s", formatMB(int64(g.perDay))), g.samples))
+	}
+}
@@ -0,0 +1,74 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import "github.com/eiannone/keyboard"
+
+// Mouse buttons reported in MouseEvent.Button
+const (
+	MOUSE_LEFT = iota
+	MOUSE_MIDDLE
+	MOUSE_RIGHT
+	MOUSE_WHEEL_UP
+	MOUSE_WHEEL_DOWN
+)
+
+// MOUSE_WHEEL_LINES is how far one notch of the scroll wheel moves the cursor
+const MOUSE_WHEEL_LINES = 3
+
+// MouseEvent is a mouse button press or a turn of the scroll wheel
+type MouseEvent struct {
+	X, Y   int // Screen cell, 1-based
+	Button int
+}
+
+// inputEvent is a key press or, if Mouse is set, a mouse event with an empty KeyEvent
+type inputEvent struct {
+	keyboard.KeyEvent
+	Mouse *MouseEvent
+}
+
+// mouseEnabled is set by openInput when the terminal reports mouse events
+var mouseEnabled bool
+
+// keyboardInput delivers the key presses read by the keyboard package, the input path whenever mouse events aren't needed.
+// The channel is closed when the keyboard package stops reading.
+func keyboardInput() (<-chan inputEvent, error) {
+	keys, err := keyboard.GetKeys(10)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan inputEvent)
+	go func() {
+		defer close(events)
+		for ev := range keys {
+			events <- inputEvent{KeyEvent: ev}
+		}
+	}()
+	return events, nil
+}
+
+// click returns the mouse event if ev is a press of the left button
+func (ev inputEvent) click() (*MouseEvent, bool) {
+	if ev.Mouse != nil && ev.Mouse.Button == MOUSE_LEFT {
+		return ev.Mouse, true
+	}
+	return nil, false
+}
@@ -0,0 +1,227 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/eiannone/keyboard"
+	"golang.org/x/sys/unix"
+)
+
+// Key presses are read by the keyboard package, like on Windows. It turns every escape sequence it doesn't
+// know into a lone ESC though, mouse reports included, so while mouse reporting is on the terminal is read
+// here instead and only the KeyEvent type of the keyboard package is kept. parseInput decodes the keys the
+// menus use the same way the keyboard package does, see cc_input_unix_test.go.
+var input struct {
+	tty  *os.File
+	orig *unix.Termios
+	done chan struct{}
+}
+
+// openInput switches the terminal to raw mode and delivers key presses and, if enabled, mouse events.
+// The channel is closed when reading stops, after an error or closeInput.
+func openInput() (<-chan inputEvent, error) {
+	if !cfg.Mouse || !termControl {
+		return keyboardInput()
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	orig, err := unix.IoctlGetTermios(int(tty.Fd()), ioctlGetTermios)
+	if err != nil {
+		tty.Close()
+		return nil, err
+	}
+	raw := *orig
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(int(tty.Fd()), ioctlSetTermios, &raw); err != nil {
+		tty.Close()
+		return nil, err
+	}
+	input.tty, input.orig, input.done = tty, orig, make(chan struct{})
+
+	// Button presses and the wheel in SGR encoding, which has no limit on the screen size
+	mouseEnabled = true
+	tty.WriteString("\033[?1000h\033[?1006h")
+
+	events := make(chan inputEvent, 10)
+	go func(done chan struct{}) {
+		defer close(events)
+		buf := make([]byte, 256)
+		var pending []byte
+		for {
+			n, err := tty.Read(buf)
+			if err != nil {
+				select {
+				case events <- inputEvent{KeyEvent: keyboard.KeyEvent{Err: err}}:
+				case <-done:
+				}
+				return
+			}
+			var parsed []inputEvent
+			parsed, pending = parseInput(append(pending, buf[:n]...))
+			if len(pending) == 1 && pending[0] == '\033' && !inputWaiting(tty) {
+				parsed = append(parsed, inputEvent{KeyEvent: keyboard.KeyEvent{Key: keyboard.KeyEsc}})
+				pending = nil
+			}
+			for _, ev := range parsed {
+				select {
+				case events <- ev:
+				case <-done:
+					return
+				}
+			}
+		}
+	}(input.done)
+	return events, nil
+}
+
+// closeInput restores the terminal, it is safe to call more than once
+func closeInput() {
+	if input.tty == nil {
+		keyboard.Close()
+		return
+	}
+	if mouseEnabled {
+		input.tty.WriteString("\033[?1000l\033[?1006l")
+		mouseEnabled = false
+	}
+	close(input.done)
+	unix.IoctlSetTermios(int(input.tty.Fd()), ioctlSetTermios, input.orig)
+	input.tty.Close()
+	input.tty = nil
+}
+
+// ESC_DELAY_MS is how long a lone ESC may wait for the rest of its sequence
+const ESC_DELAY_MS = 25
+
+// inputWaiting reports whether more input arrives within ESC_DELAY_MS, telling a pressed ESC key
+// apart from the start of an escape sequence that was split over two reads
+func inputWaiting(tty *os.File) bool {
+	n, err := unix.Poll([]unix.PollFd{{Fd: int32(tty.Fd()), Events: unix.POLLIN}}, ESC_DELAY_MS)
+	return err == nil && n > 0
+}
+
+// csiKeys maps the final byte (and number, for '~') of cursor key sequences to keys
+var csiKeys = map[string]keyboard.Key{
+	"A": keyboard.KeyArrowUp, "B": keyboard.KeyArrowDown, "C": keyboard.KeyArrowRight, "D": keyboard.KeyArrowLeft,
+	"H": keyboard.KeyHome, "F": keyboard.KeyEnd,
+	"1~": keyboard.KeyHome, "7~": keyboard.KeyHome, "4~": keyboard.KeyEnd, "8~": keyboard.KeyEnd,
+	"2~": keyboard.KeyInsert, "3~": keyboard.KeyDelete, "5~": keyboard.KeyPgup, "6~": keyboard.KeyPgdn,
+}
+
+// parseInput decodes as many events from data as possible and returns the bytes of an unfinished sequence
+func parseInput(data []byte) (events []inputEvent, rest []byte) {
+	key := func(k keyboard.Key) { events = append(events, inputEvent{KeyEvent: keyboard.KeyEvent{Key: k}}) }
+	for len(data) > 0 {
+		b := data[0]
+		switch {
+		case b == '\033' && len(data) == 1:
+			// The ESC key itself or the start of a sequence, the reader decides once the terminal is quiet
+			return events, data
+
+		case b == '\033' && (data[1] == '[' || data[1] == 'O'):
+			// CSI: parameter bytes, then a final byte; SS3: a single final byte
+			end := 2
+			if data[1] == '[' {
+				for end < len(data) && data[end] >= 0x20 && data[end] <= 0x3f {
+					end++
+				}
+			}
+			if end >= len(data) {
+				return events, data
+			}
+			params, final := string(data[2:end]), string(data[end])
+			data = data[end+1:]
+			if strings.HasPrefix(params, "<") {
+				if m, ok := parseSGRMouse(params[1:], final); ok {
+					events = append(events, inputEvent{Mouse: &m})
+				}
+				continue
+			}
+			if final == "~" {
+				final = strings.SplitN(params, ";", 2)[0] + final
+			}
+			if k, ok := csiKeys[final]; ok {
+				key(k)
+			}
+
+		case b == '\033':
+			// Alt+key arrives as ESC followed by the key, reported like the keyboard package does
+			r, n := utf8.DecodeRune(data[1:])
+			events = append(events, inputEvent{KeyEvent: keyboard.KeyEvent{Key: keyboard.KeyEsc, Rune: r}})
+			data = data[1+n:]
+
+		case b < 0x20 || b == 0x7f || b == ' ':
+			key(keyboard.Key(b))
+			data = data[1:]
+
+		default:
+			if !utf8.FullRune(data) {
+				return events, data
+			}
+			r, n := utf8.DecodeRune(data)
+			events = append(events, inputEvent{KeyEvent: keyboard.KeyEvent{Rune: r}})
+			data = data[n:]
+		}
+	}
+	return events, nil
+}
+
+// parseSGRMouse decodes the "button;x;y" of an SGR mouse report. Releases and movement are dropped.
+func parseSGRMouse(params, final string) (MouseEvent, bool) {
+	f := strings.Split(params, ";")
+	if len(f) != 3 || final != "M" {
+		return MouseEvent{}, false
+	}
+	var n [3]int
+	for i := range f {
+		v, err := strconv.Atoi(f[i])
+		if err != nil {
+			return MouseEvent{}, false
+		}
+		n[i] = v
+	}
+	m := MouseEvent{X: n[1], Y: n[2]}
+	switch {
+	case n[0]&32 != 0: // Movement
+		return m, false
+	case n[0]&64 != 0:
+		m.Button = MOUSE_WHEEL_UP + n[0]&1
+	default:
+		m.Button = n[0] & 3
+		if m.Button == 3 {
+			return m, false
+		}
+	}
+	return m, true
+}
@@ -0,0 +1,89 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+//go:build !windows
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eiannone/keyboard"
+)
+
+func TestParseInput(t *testing.T) {
+	key := func(k keyboard.Key) inputEvent { return inputEvent{KeyEvent: keyboard.KeyEvent{Key: k}} }
+	char := func(r rune) inputEvent { return inputEvent{KeyEvent: keyboard.KeyEvent{Rune: r}} }
+	mouse := func(button, x, y int) inputEvent { return inputEvent{Mouse: &MouseEvent{X: x, Y: y, Button: button}} }
+
+	tests := []struct {
+		name string
+		in   string
+		want []inputEvent
+		rest string
+	}{
+		{"rune", "q", []inputEvent{char('q')}, ""},
+		{"utf-8", "ä", []inputEvent{char('ä')}, ""},
+		{"control keys", "\r \x03\x7f\t", []inputEvent{key(keyboard.KeyEnter), key(keyboard.KeySpace), key(keyboard.KeyCtrlC), key(keyboard.KeyBackspace2), key(keyboard.KeyTab)}, ""},
+		{"lone escape", "\x1b", nil, "\x1b"},
+		{"escape after a key", "q\x1b", []inputEvent{char('q')}, "\x1b"},
+		{"alt+key", "\x1bx", []inputEvent{{KeyEvent: keyboard.KeyEvent{Key: keyboard.KeyEsc, Rune: 'x'}}}, ""},
+		{"csi arrows", "\x1b[A\x1b[B\x1b[C\x1b[D", []inputEvent{key(keyboard.KeyArrowUp), key(keyboard.KeyArrowDown), key(keyboard.KeyArrowRight), key(keyboard.KeyArrowLeft)}, ""},
+		{"ss3 arrows", "\x1bOA\x1bOB", []inputEvent{key(keyboard.KeyArrowUp), key(keyboard.KeyArrowDown)}, ""},
+		{"modified arrow", "\x1b[1;5C", []inputEvent{key(keyboard.KeyArrowRight)}, ""},
+		{"tilde keys", "\x1b[5~\x1b[6~\x1b[1~\x1b[4~\x1b[3~", []inputEvent{key(keyboard.KeyPgup), key(keyboard.KeyPgdn), key(keyboard.KeyHome), key(keyboard.KeyEnd), key(keyboard.KeyDelete)}, ""},
+		{"home and end", "\x1b[H\x1bOF", []inputEvent{key(keyboard.KeyHome), key(keyboard.KeyEnd)}, ""},
+		{"unknown sequence", "\x1b[99~j", []inputEvent{char('j')}, ""},
+		{"keys around a sequence", "j\x1b[Bk", []inputEvent{char('j'), key(keyboard.KeyArrowDown), char('k')}, ""},
+		{"left click", "\x1b[<0;10;5M", []inputEvent{mouse(MOUSE_LEFT, 10, 5)}, ""},
+		{"right click", "\x1b[<2;3;4M", []inputEvent{mouse(MOUSE_RIGHT, 3, 4)}, ""},
+		{"wheel", "\x1b[<64;1;2M\x1b[<65;1;2M", []inputEvent{mouse(MOUSE_WHEEL_UP, 1, 2), mouse(MOUSE_WHEEL_DOWN, 1, 2)}, ""},
+		{"click beyond column 223", "\x1b[<0;300;80M", []inputEvent{mouse(MOUSE_LEFT, 300, 80)}, ""},
+		{"release and movement dropped", "\x1b[<0;10;5m\x1b[<32;11;5M\x1b[<35;12;5Mq", []inputEvent{char('q')}, ""},
+		{"malformed mouse report", "\x1b[<0;10M\x1b[<0;1;1;1M\x1b[<;;M", nil, ""},
+		{"split mouse report", "q\x1b[<0;1", []inputEvent{char('q')}, "\x1b[<0;1"},
+		{"split csi", "\x1b[", nil, "\x1b["},
+		{"split utf-8", "\xc3", nil, "\xc3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rest := parseInput([]byte(tt.in))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseInput(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+			if string(rest) != tt.rest {
+				t.Errorf("parseInput(%q) left %q, want %q", tt.in, rest, tt.rest)
+			}
+		})
+	}
+}
+
+// A sequence split over two reads decodes like one arriving at once
+func TestParseInputResumes(t *testing.T) {
+	in := "a\x1b[<0;12;7M\x1b[6~ä"
+	whole, _ := parseInput([]byte(in))
+	for i := 1; i < len(in); i++ {
+		first, rest := parseInput([]byte(in[:i]))
+		second, rest := parseInput(append(rest, in[i:]...))
+		if got := append(first, second...); !reflect.DeepEqual(got, whole) || len(rest) != 0 {
+			t.Errorf("split at %d: got %+v (rest %q), want %+v", i, got, rest, whole)
+		}
+	}
+}
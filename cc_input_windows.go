@@ -0,0 +1,34 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+//go:build windows
+
+package main
+
+import "github.com/eiannone/keyboard"
+
+// openInput delivers the key presses of the console. Mouse events are not reported on Windows.
+func openInput() (<-chan inputEvent, error) {
+	return keyboardInput()
+}
+
+// closeInput restores the console, it is safe to call more than once
+func closeInput() {
+	keyboard.Close()
+}
@@ -0,0 +1,256 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eiannone/keyboard"
+)
+
+// ========================= CANCELLATION =========================
+
+// cancelState connects interrupts with the running cleanup
+var cancelState struct {
+	sync.Mutex
+	ch        chan struct{} // Set while a cleanup can be cancelled, closed to cancel it
+	requested bool
+}
+
+// startCancellable makes the next interrupt stop the cleanup that is about to start instead of exiting.
+// The channel is for CleanOptions.Cancel, done must be called when the cleanup returns.
+func startCancellable() (cancel <-chan struct{}, done func()) {
+	cancelState.Lock()
+	defer cancelState.Unlock()
+	ch := make(chan struct{})
+	cancelState.ch, cancelState.requested = ch, false
+	return ch, func() {
+		cancelState.Lock()
+		cancelState.ch = nil
+		cancelState.Unlock()
+	}
+}
+
+// requestCancel asks the running cleanup to stop after the current path.
+// It returns false if no cleanup is running or it was asked before, then the caller should quit at once.
+func requestCancel() bool {
+	cancelState.Lock()
+	defer cancelState.Unlock()
+	if cancelState.ch == nil || cancelState.requested {
+		return false
+	}
+	cancelState.requested = true
+	close(cancelState.ch)
+	logWarn(tr("cancel.requested"))
+	return true
+}
+
+// cancelOnCtrlC watches the keyboard during a cleanup, since raw input mode turns Ctrl+C into a key press.
+// stop must be called before the keys are read elsewhere again.
+func cancelOnCtrlC(keys <-chan inputEvent) (stop func()) {
+	quit := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		for {
+			select {
+			case ev, ok := <-keys:
+				if !ok {
+					keys = nil // The reader stopped, only quit is left to wait for
+					continue
+				}
+				if ev.Key == keyboard.KeyCtrlC && !requestCancel() {
+					cc_exit(EXIT_ABORTED)
+				}
+			case <-quit:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(quit)
+		<-exited
+	}
+}
+
+// globEscape quotes the glob characters of a path, so it only matches itself
+func globEscape(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		switch {
+		case r == '*' || r == '?' || r == '[':
+			b.WriteString("[" + string(r) + "]")
+		case r == '\\' && GOOS != "windows":
+			b.WriteString(`\\`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// checkedPrograms returns the checked Programs of a list
+func checkedPrograms(programs []Program) []Program {
+	var list []Program
+	for _, p := range programs {
+		if p.Checked {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+// ========================= JOURNAL =========================
+
+// Journal records an interrupted cleanup until 'crunchycleaner resume' finishes it
+type Journal struct {
+	Time      time.Time        `json:"time"` // Start of the interrupted session
+	User      string           `json:"user"`
+	Completed []HistoryProgram `json:"completed"` // Of this and earlier interrupted runs of the session
+	Pending   []JournalProgram `json:"pending"`
+}
+
+// JournalProgram is a Program that still has to be cleaned
+type JournalProgram struct {
+	Name    string   `json:"name"`
+	Paths   []string `json:"paths"` // Glob patterns like in the catalog
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// resumed is the journal 'crunchycleaner resume' is working on, nil otherwise
+var resumed *Journal
+
+func journalFile() string {
+	return filepath.Join(stateDir(), "journal.json")
+}
+
+// newJournal describes a cancelled cleanup, continuing the resumed session if there is one
+func newJournal(r *CleanResult) *Journal {
+	j := &Journal{Time: r.Start, User: currentUsername()}
+	if resumed != nil {
+		j.Time, j.Completed = resumed.Time, resumed.Completed
+	}
+	for _, p := range r.Programs {
+		j.Completed = append(j.Completed, HistoryProgram{p.Name, p.Bytes, p.Paths, len(p.Errors)})
+	}
+	for _, p := range r.Pending {
+		jp := JournalProgram{Name: p.Name, Paths: p.Paths}
+		for _, e := range p.Exclude {
+			jp.Exclude = append(jp.Exclude, e.Path)
+		}
+		j.Pending = append(j.Pending, jp)
+	}
+	return j
+}
+
+// saveJournal writes the journal of a cancelled cleanup and tells how to finish it.
+// Dry runs didn't delete anything, so there is nothing to resume.
+func saveJournal(r *CleanResult) {
+	if r.DryRun {
+		return
+	}
+	data, err := json.MarshalIndent(newJournal(r), "", "  ")
+	if err == nil {
+		err = os.MkdirAll(stateDir(), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(journalFile(), append(data, '\n'), 0o644)
+	}
+	if err != nil {
+		logWarn(tr("journal.error", err))
+		return
+	}
+	logInfo(tr("journal.saved", journalFile()))
+}
+
+// loadJournal reads the journal of the last interrupted cleanup, nil if there is none
+func loadJournal() (*Journal, error) {
+	data, err := os.ReadFile(journalFile())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var j Journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("%s: %w", journalFile(), err)
+	}
+	return &j, nil
+}
+
+// programs turns the pending work back into checked and scanned Programs
+func (j *Journal) programs() []Program {
+	var programs []Program
+	for _, jp := range j.Pending {
+		p := Program{Name: jp.Name, Paths: jp.Paths, Checked: true}
+		for _, e := range jp.Exclude {
+			p.Exclude = append(p.Exclude, PathInfo{Path: e})
+		}
+		p.scan()
+		programs = append(programs, p)
+	}
+	return programs
+}
+
+// ========================= RESUME COMMAND =========================
+
+// resumeCommand implements 'crunchycleaner resume', which finishes an interrupted cleanup
+func resumeCommand(args []string) int {
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Usage:\n  crunchycleaner resume\n")
+		return EXIT_USAGE
+	}
+	j, err := loadJournal()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, tr("journal.read_error", err))
+		return EXIT_PARTIAL
+	}
+	if j == nil {
+		fmt.Println(tr("journal.none"))
+		return EXIT_NOTHING
+	}
+
+	var done int64
+	for _, p := range j.Completed {
+		done += p.Bytes
+	}
+	fmt.Println(tr("journal.resuming", j.Time.Local().Format("2006-01-02 15:04"), formatMB(done), len(j.Completed), len(j.Pending)))
+	resumed = j
+	result := runCleanup(j.programs())
+	if !result.Cancelled && !result.DryRun {
+		if err := os.Remove(journalFile()); err != nil {
+			logWarn(tr("journal.remove_error", err))
+		}
+	}
+	if len(result.Programs) == 0 && !result.Cancelled {
+		// Everything left was gone already, which still completes the session
+		return EXIT_OK
+	}
+	return result.ExitCode()
+}
@@ -0,0 +1,308 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/eiannone/keyboard"
+)
+
+// Menu actions that can be bound to keys in the [keys] config table
+const (
+	ACT_UP         = "up"
+	ACT_DOWN       = "down"
+	ACT_PAGE_UP    = "page_up"
+	ACT_PAGE_DOWN  = "page_down"
+	ACT_TOP        = "top"
+	ACT_BOTTOM     = "bottom"
+	ACT_TOGGLE     = "toggle"
+	ACT_TOGGLE_ALL = "toggle_all"
+	ACT_PRESET     = "preset"
+	ACT_SORT       = "sort"
+	ACT_FILTER     = "filter"
+	ACT_BROWSE     = "browse"
+	ACT_PARENT     = "parent"
+	ACT_CLEAN      = "clean"
+	ACT_HELP       = "help"
+	ACT_DELETE     = "delete"
+	ACT_CATALOG    = "catalog"
+	ACT_CLOSE      = "close"
+)
+
+// Where an action applies. A key may be bound once in the menu and once in the explorer,
+// but not to two actions that can be triggered in the same place.
+const (
+	SCOPE_ALL     = ""
+	SCOPE_MENU    = "menu" // The menu and its path browser
+	SCOPE_EXPLORE = "explore"
+)
+
+// keyActions lists every action in help order with its scope and default keys, the description is the message "help.<name>".
+// Single characters are case-sensitive, the first key is the one shown in hints.
+var keyActions = []struct {
+	name  string
+	scope string
+	keys  []string
+}{
+	{ACT_UP, SCOPE_ALL, []string{"up", "W", "w"}},
+	{ACT_DOWN, SCOPE_ALL, []string{"down", "S", "s"}},
+	{ACT_PAGE_UP, SCOPE_ALL, []string{"pgup"}},
+	{ACT_PAGE_DOWN, SCOPE_ALL, []string{"pgdn"}},
+	{ACT_TOP, SCOPE_ALL, []string{"home"}},
+	{ACT_BOTTOM, SCOPE_ALL, []string{"end"}},
+	{ACT_TOGGLE, SCOPE_ALL, []string{"enter", "space"}},
+	{ACT_TOGGLE_ALL, SCOPE_MENU, []string{"A", "a"}},
+	{ACT_PRESET, SCOPE_MENU, []string{"P", "p"}},
+	{ACT_SORT, SCOPE_MENU, []string{"O", "o"}},
+	{ACT_FILTER, SCOPE_MENU, []string{"/"}},
+	{ACT_BROWSE, SCOPE_ALL, []string{"right"}},
+	{ACT_PARENT, SCOPE_ALL, []string{"left", "backspace"}},
+	{ACT_CLEAN, SCOPE_MENU, []string{"C", "c"}},
+	{ACT_HELP, SCOPE_MENU, []string{"?"}},
+	{ACT_DELETE, SCOPE_EXPLORE, []string{"D", "d"}},
+	{ACT_CATALOG, SCOPE_EXPLORE, []string{"A", "a"}},
+	{ACT_CLOSE, SCOPE_EXPLORE, []string{"Q", "q"}},
+}
+
+// actionScope returns the scope of an action
+func actionScope(action string) string {
+	for _, a := range keyActions {
+		if a.name == action {
+			return a.scope
+		}
+	}
+	return SCOPE_ALL
+}
+
+// scopesOverlap reports whether actions of the two scopes can be triggered in the same place
+func scopesOverlap(a, b string) bool {
+	return a == SCOPE_ALL || b == SCOPE_ALL || a == b
+}
+
+// keyNames are the names of the special keys that can be bound; ESC and Ctrl+C keep their meaning
+var keyNames = map[keyboard.Key]string{
+	keyboard.KeyArrowUp:    "up",
+	keyboard.KeyArrowDown:  "down",
+	keyboard.KeyArrowLeft:  "left",
+	keyboard.KeyArrowRight: "right",
+	keyboard.KeyPgup:       "pgup",
+	keyboard.KeyPgdn:       "pgdn",
+	keyboard.KeyHome:       "home",
+	keyboard.KeyEnd:        "end",
+	keyboard.KeyInsert:     "insert",
+	keyboard.KeyDelete:     "delete",
+	keyboard.KeyEnter:      "enter",
+	keyboard.KeySpace:      "space",
+	keyboard.KeyTab:        "tab",
+	keyboard.KeyBackspace:  "backspace",
+	keyboard.KeyBackspace2: "backspace",
+}
+
+// keyLabels is how special keys are shown in hints and the help
+var keyLabels = map[string]string{
+	"up": "↑", "down": "↓", "left": "←", "right": "→", "pgup": "PgUp", "pgdn": "PgDn", "home": "Home", "end": "End",
+	"insert": "INS", "delete": "DEL", "enter": "ENTER", "space": "SPACE", "tab": "TAB", "backspace": "BACKSPACE",
+}
+
+// Keymap binds every key to at most one action per scope
+type Keymap struct {
+	actions map[string]map[string]string // Scope -> key name -> action
+	keys    map[string][]string          // Action -> key names, in hint order
+}
+
+// bindings is the active keymap, replaced by setupKeymap
+var bindings, _ = newKeymap(nil)
+
+// newKeymap builds a keymap from the defaults and the [keys] overrides, which replace all default keys of an action.
+// A key given in the config is taken away from the actions in the same place it belongs to by default,
+// so a conflict only remains if two overrides share a key or an action ends up without any key.
+func newKeymap(overrides map[string][]string) (*Keymap, error) {
+	k := &Keymap{actions: map[string]map[string]string{}, keys: map[string][]string{}}
+	known := map[string]bool{}
+	for _, a := range keyActions {
+		known[a.name] = true
+	}
+	claimed := map[string][]string{} // Key -> overriding actions
+	names := make([]string, 0, len(overrides))
+	for action := range overrides {
+		names = append(names, action)
+	}
+	sort.Strings(names)
+	for _, action := range names {
+		if !known[action] {
+			return nil, fmt.Errorf("unknown action %q", action)
+		}
+		for _, key := range overrides[action] {
+			key, err := parseKeyName(key)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", action, err)
+			}
+			for _, other := range claimed[key] {
+				if other != action && scopesOverlap(actionScope(other), actionScope(action)) {
+					return nil, fmt.Errorf("key %q is bound to both %s and %s", key, other, action)
+				}
+			}
+			claimed[key] = append(claimed[key], action)
+		}
+	}
+
+	for _, a := range keyActions {
+		list, ok := overrides[a.name]
+		if !ok {
+			list = a.keys
+		}
+		for _, key := range list {
+			key, _ = parseKeyName(key)
+			if k.bound(key, a.name, a.scope, claimed[key]) {
+				continue
+			}
+			if k.actions[a.scope] == nil {
+				k.actions[a.scope] = map[string]string{}
+			}
+			k.actions[a.scope][key] = a.name
+			k.keys[a.name] = append(k.keys[a.name], key)
+		}
+		if len(k.keys[a.name]) == 0 {
+			return nil, fmt.Errorf("%s has no key left, bind it to another key", a.name)
+		}
+	}
+	return k, nil
+}
+
+// bound reports whether key already triggers another action where action applies,
+// or is claimed there by one of the overrides
+func (k *Keymap) bound(key, action, scope string, claimed []string) bool {
+	for _, owner := range claimed {
+		if owner != action && scopesOverlap(actionScope(owner), scope) {
+			return true
+		}
+	}
+	for s, keys := range k.actions {
+		if _, ok := keys[key]; ok && scopesOverlap(s, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// setupKeymap applies the [keys] table of the config
+func setupKeymap(c *Config) error {
+	k, err := newKeymap(c.Keys)
+	if err != nil {
+		return err
+	}
+	bindings = k
+	return nil
+}
+
+// parseKeyName checks a key from the config and returns its canonical name:
+// a single (case-sensitive) character or one of the special key names
+func parseKeyName(key string) (string, error) {
+	if key == " " {
+		return "space", nil
+	}
+	if utf8.RuneCountInString(key) == 1 {
+		return key, nil
+	}
+	key = strings.ToLower(key)
+	if _, ok := keyLabels[key]; ok {
+		return key, nil
+	}
+	return "", fmt.Errorf("unknown key %q", key)
+}
+
+// keyName returns the name of a pressed key, "" for keys that can't be bound
+func keyName(ev keyboard.KeyEvent) string {
+	if ev.Rune == ' ' {
+		return "space"
+	}
+	if ev.Rune != 0 {
+		return string(ev.Rune)
+	}
+	return keyNames[ev.Key]
+}
+
+// action returns the action bound to the pressed key in the menu, or ""
+func (k *Keymap) action(ev inputEvent) string {
+	return k.actionIn(SCOPE_MENU, ev)
+}
+
+// actionIn returns the action bound to the pressed key in the given scope, or ""
+func (k *Keymap) actionIn(scope string, ev inputEvent) string {
+	name := keyName(ev.KeyEvent)
+	if a, ok := k.actions[scope][name]; ok {
+		return a
+	}
+	return k.actions[SCOPE_ALL][name]
+}
+
+// label returns how the first key of an action is shown in hints, e.g. "C" or "↑"
+func (k *Keymap) label(action string) string {
+	return keyLabel(k.keys[action][0])
+}
+
+func keyLabel(key string) string {
+	if l, ok := keyLabels[key]; ok {
+		return l
+	}
+	return key
+}
+
+// helpLines lists every action with its keys for the help overlay
+func (k *Keymap) helpLines() []string {
+	var lines []string
+	for _, a := range keyActions {
+		var labels []string
+		for _, key := range k.keys[a.name] {
+			labels = append(labels, keyLabel(key))
+		}
+		lines = append(lines, fmt.Sprintf("  %s%-18s%s %s", YELLOW, strings.Join(labels, ", "), RC, tr("help."+a.name)))
+	}
+	return append(lines,
+		fmt.Sprintf("  %s%-18s%s %s", YELLOW, "ESC", RC, tr("help.esc")),
+		fmt.Sprintf("  %s%-18s%s %s", YELLOW, "Ctrl+C", RC, tr("help.quit")))
+}
+
+// showHelp draws the key bindings over the whole screen until a key is pressed
+func showHelp(keys <-chan inputEvent, resized <-chan struct{}) {
+	for {
+		cols, _ := screenSize()
+		fmt.Print(CLEAR_SCREEN)
+		fmt.Printf("%s\n", fitWidth(CYAN+tr("help.title")+RC+" "+tr("help.config"), cols))
+		for _, l := range bindings.helpLines() {
+			fmt.Printf("%s\n", fitWidth(l, cols))
+		}
+		fmt.Printf("\n%s", fitWidth(tr("help.back"), cols))
+		select {
+		case ev := <-keys:
+			if ev.Key == keyboard.KeyCtrlC {
+				cc_exit(EXIT_ABORTED)
+			}
+			if _, ok := ev.click(); ev.Mouse == nil || ok {
+				return
+			}
+		case <-resized:
+		}
+	}
+}
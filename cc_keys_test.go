@@ -0,0 +1,71 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"testing"
+
+	"github.com/eiannone/keyboard"
+)
+
+func TestKeymapScopes(t *testing.T) {
+	press := func(r rune) inputEvent { return inputEvent{KeyEvent: keyboard.KeyEvent{Rune: r}} }
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		key       rune
+		menu      string
+		explore   string
+	}{
+		{"defaults share a", nil, 'a', ACT_TOGGLE_ALL, ACT_CATALOG},
+		{"explorer only", nil, 'd', "", ACT_DELETE},
+		{"navigation everywhere", nil, 's', ACT_DOWN, ACT_DOWN},
+		{"override takes the key from the explorer", map[string][]string{ACT_DOWN: {"down", "d"}}, 'd', ACT_DOWN, ACT_DOWN},
+		{"menu and explorer overrides share a key", map[string][]string{ACT_SORT: {"x"}, ACT_CLOSE: {"x"}}, 'x', ACT_SORT, ACT_CLOSE},
+		{"menu override leaves the explorer key", map[string][]string{ACT_PRESET: {"q"}}, 'q', ACT_PRESET, ACT_CLOSE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := newKeymap(tt.overrides)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := k.actionIn(SCOPE_MENU, press(tt.key)); got != tt.menu {
+				t.Errorf("menu: %q does %q, want %q", tt.key, got, tt.menu)
+			}
+			if got := k.actionIn(SCOPE_EXPLORE, press(tt.key)); got != tt.explore {
+				t.Errorf("explorer: %q does %q, want %q", tt.key, got, tt.explore)
+			}
+		})
+	}
+}
+
+func TestKeymapConflicts(t *testing.T) {
+	for _, overrides := range []map[string][]string{
+		{ACT_DOWN: {"x"}, ACT_DELETE: {"x"}},
+		{ACT_SORT: {"x"}, ACT_CLEAN: {"x"}},
+		{ACT_CATALOG: {"x"}, ACT_CLOSE: {"x"}},
+		{ACT_DOWN: {"D", "d"}},
+	} {
+		if _, err := newKeymap(overrides); err == nil {
+			t.Errorf("newKeymap(%v) succeeded, want a conflict", overrides)
+		}
+	}
+}
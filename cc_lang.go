@@ -0,0 +1,165 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// catalogs holds the UI messages per language, English is the reference every other catalog is checked against
+var catalogs = map[string]map[string]string{
+	"en": messagesEN,
+	"de": messagesDE,
+}
+
+// language is the active message catalog, set by setupLanguage
+var language = "en"
+
+// tr returns the message for key in the active language, formatted with args like fmt.Sprintf.
+// A message missing from the catalog falls back to English.
+func tr(key string, args ...any) string {
+	msg, ok := catalogs[language][key]
+	if !ok {
+		msg, ok = messagesEN[key]
+	}
+	if !ok {
+		msg = key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// setupLanguage selects the catalog: the configured one, or else the one of the user's locale, falling back to English
+func setupLanguage(name string) error {
+	if name != "" {
+		if _, ok := catalogs[name]; !ok {
+			return fmt.Errorf("unsupported language %q (available: %s)", name, strings.Join(languageNames(), ", "))
+		}
+		language = name
+		return nil
+	}
+	if l := detectLanguage(); catalogs[l] != nil {
+		language = l
+	}
+	return nil
+}
+
+// detectLanguage returns the language code of the user's locale: LC_ALL, LC_MESSAGES or LANG as POSIX defines it,
+// the user interface language on Windows
+func detectLanguage() string {
+	locale := ""
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale = os.Getenv(env); locale != "" {
+			break
+		}
+	}
+	if locale == "" {
+		locale = userLocale()
+	}
+	// de_DE.UTF-8, de-DE or de@euro -> de
+	if i := strings.IndexAny(locale, "_-.@"); i >= 0 {
+		locale = locale[:i]
+	}
+	return strings.ToLower(locale)
+}
+
+// languageNames returns the codes of all catalogs in alphabetical order
+func languageNames() []string {
+	var names []string
+	for name := range catalogs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var formatVerb = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d+)?[a-zA-Z%]`)
+
+// verbs returns the formatting verbs of a message, sorted, so reordered arguments still compare equal
+func verbs(msg string) []string {
+	var list []string
+	for _, v := range formatVerb.FindAllString(msg, -1) {
+		list = append(list, v[len(v)-1:])
+	}
+	sort.Strings(list)
+	return list
+}
+
+// checkCatalogs compares every catalog with the English one and describes missing and unknown keys
+// and messages whose formatting verbs differ
+func checkCatalogs() []string {
+	var problems []string
+	for _, lang := range languageNames() {
+		msgs := catalogs[lang]
+		for key, en := range messagesEN {
+			msg, ok := msgs[key]
+			switch {
+			case !ok:
+				problems = append(problems, fmt.Sprintf("%s: missing %q", lang, key))
+			case !slices.Equal(verbs(msg), verbs(en)):
+				problems = append(problems, fmt.Sprintf("%s: %q has the verbs %v, English has %v", lang, key, verbs(msg), verbs(en)))
+			}
+		}
+		for key := range msgs {
+			if _, ok := messagesEN[key]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: unknown key %q", lang, key))
+			}
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// langCommand implements 'crunchycleaner lang list|check'
+func langCommand(args []string) int {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+	switch args[0] {
+	case "list":
+		for _, name := range languageNames() {
+			active := ""
+			if name == language {
+				active = " (active)"
+			}
+			fmt.Printf("%s%s\n", name, active)
+		}
+		return EXIT_OK
+	case "check":
+		problems := checkCatalogs()
+		for _, p := range problems {
+			fmt.Println(p)
+		}
+		if len(problems) > 0 {
+			return EXIT_PARTIAL
+		}
+		fmt.Printf("%d catalogs with %d messages each, all complete\n", len(catalogs), len(messagesEN))
+		return EXIT_OK
+	}
+	fmt.Fprintf(os.Stderr, "Usage:\n  crunchycleaner lang list\n  crunchycleaner lang check\n")
+	return EXIT_USAGE
+}
@@ -0,0 +1,238 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+// messagesDE is the German catalog
+var messagesDE = map[string]string{
+	// ===== GENERAL =====
+	"app.init":       "CrunchyCleaner %s wird gestartet...",
+	"app.exit":       "CrunchyCleaner wird beendet...",
+	"app.pause":      "[ENTER] drücken, um fortzufahren...",
+	"app.aborted":    "Abgebrochen durch %v",
+	"banner.made_by": "Erstellt von: %s",
+	"banner.version": "Version: %s",
+	"banner.disk":    "Speicherplatz: %s / %s",
+	"auto.preset":    "HINWEIS: Automatik aktiv. Scanne und wähle das Preset %q...",
+	"auto.all":       "HINWEIS: Automatik aktiv. Scanne und wähle alle Caches...",
+	"scan.spinner":   "Dateisystem wird durchsucht",
+	"list.empty":     "(leer)",
+	"list.range":     "%d-%d von %d",
+
+	// ===== MENU =====
+	"menu.hint":           "%s/%s zum Navigieren | [%s] zum Auswählen | [%s] zum Bereinigen | [%s] Hilfe",
+	"menu.found":          "Ordner gefunden: [%d]",
+	"menu.found_filtered": "Ordner gefunden: [%d/%d]",
+	"menu.header":         "%s | [%s] Sortierung: %s | [%s] Filter: %s",
+	"menu.no_matches":     "Keine Treffer",
+	"menu.preset_custom":  "eigenes",
+	"menu.status":         "Preset: %s | [%s] zum Wechseln",
+	"menu.button":         "[ Bereinigen ]",
+	"menu.none_found":     "Keine Cache-Ordner auf deinem System gefunden",
+	"menu.none_left":      "Keine Cache-Ordner mehr auf deinem System",
+	"sort.catalog":        "Katalog",
+	"sort.size":           "Größe",
+	"sort.name":           "Name",
+	"sort.category":       "Kategorie",
+	"sort.age":            "Alter",
+	"detail.title":        "%s: %d Pfade, %d Dateien",
+	"detail.excluded":     ", %d ausgeschlossen",
+	"detail.browse":       "[%s] durchsuchen",
+	"detail.more":         "... und %d weitere Pfade",
+	"detail.files":        "%6d Dateien",
+
+	// ===== HELP =====
+	"help.up":         "Cursor nach oben",
+	"help.down":       "Cursor nach unten",
+	"help.page_up":    "Eine Seite nach oben",
+	"help.page_down":  "Eine Seite nach unten",
+	"help.top":        "Zum ersten Eintrag springen",
+	"help.bottom":     "Zum letzten Eintrag springen",
+	"help.toggle":     "Eintrag auswählen",
+	"help.toggle_all": "Alle auswählen (Einträge, die zum Filter passen)",
+	"help.preset":     "Preset wechseln",
+	"help.sort":       "Sortierung ändern",
+	"help.filter":     "Nach Name oder Kategorie filtern",
+	"help.browse":     "Pfade des Eintrags durchsuchen, Ordner öffnen",
+	"help.parent":     "Im Browser einen Ordner nach oben",
+	"help.clean":      "Bereinigung starten",
+	"help.help":       "Diese Hilfe anzeigen",
+	"help.delete":     "Datei oder Ordner löschen (Explorer)",
+	"help.catalog":    "Ordner zum Katalog hinzufügen (Explorer)",
+	"help.close":      "Explorer beenden",
+	"help.esc":        "Filter löschen, zurück",
+	"help.quit":       "Beenden",
+	"help.title":      "Tastenbelegung",
+	"help.config":     "(änderbar im Abschnitt [keys] der Konfiguration)",
+	"help.back":       "Beliebige Taste drücken, um zurückzugehen",
+
+	// ===== CONFIRM =====
+	"confirm.title":        "Bereinigung bestätigen",
+	"confirm.needs_rights": "%s braucht erweiterte Rechte, Dateien anderer Benutzer schlagen fehl",
+	"confirm.running":      "%s: %s läuft, bitte zuerst schließen, damit der Cache nicht benutzt wird",
+	"confirm.threshold":    "Nur Laufwerke mit weniger als %s freiem Platz werden bereinigt",
+	"confirm.total":        "Gesamt: %d Caches, %d Pfade, %d Dateien, %s",
+	"confirm.row":          "%3d Pfade %7d Dateien",
+	"confirm.more":         "... und %d weitere",
+	"confirm.mount":        "(%.2f GB von %.2f GB jetzt frei)",
+	"confirm.mode":         "Modus: %s",
+	"confirm.delete":       "Dateien werden gelöscht!",
+	"confirm.dry":          "Probelauf, nichts wird gelöscht",
+	"confirm.keys":         "[Y] zum Bereinigen | [D] Probelauf umschalten | [N] oder [ESC] zurück zum Menü",
+
+	// ===== BROWSER =====
+	"browse.matches":    "gefundene Pfade",
+	"browse.keys":       "[%s] öffnen | [%s] zurück | [%s] ein-/ausschließen | [ESC] zurück zum Menü",
+	"browse.status":     "Bereinige %s | Ausgeschlossen: %d Pfade (%s)",
+	"browse.unselected": "Nicht ausgewählt, [%s] wählt nur den Eintrag unter dem Cursor",
+
+	// ===== EXPLORER =====
+	"explore.sizing":         "Größe von %s wird ermittelt",
+	"explore.title":          "Erkunden",
+	"explore.keys":           "[%s] öffnen | [%s] zurück | [%s] löschen | [%s] zum Katalog hinzufügen | [%s] beenden",
+	"explore.entries":        "%d Einträge",
+	"explore.range":          "%d-%d von %d Einträgen",
+	"explore.dry":            "PROBELAUF",
+	"explore.confirm":        "%s (%s) löschen?",
+	"explore.yes_no":         "[Y/N]",
+	"explore.would":          "Würde löschen: %s",
+	"explore.dry_status":     "Probelauf, nichts gelöscht: %s",
+	"explore.deleted":        "%s gelöscht",
+	"explore.deleted_size":   "%s gelöscht (%s)",
+	"explore.deleted_errors": "%s mit %d Fehlern gelöscht",
+	"explore.in_catalog":     "Bereits im Katalog als %q",
+	"explore.name":           "Name im Katalog:",
+	"explore.taken":          "%q ist bereits vergeben, bitte einen anderen Namen wählen",
+	"explore.save_error":     "Speichern fehlgeschlagen: %v",
+	"explore.added":          "%q zu %s hinzugefügt",
+
+	// ===== CLEANUP =====
+	"clean.dry_run_note":      "HINWEIS: Probelauf aktiv. Es werden keine Dateien gelöscht.",
+	"clean.risk":              "Die Nutzung erfolgt auf eigene Gefahr!",
+	"clean.user":              "Benutzer: %s",
+	"clean.selection_error":   "Auswahl konnte nicht gespeichert werden: %v",
+	"clean.started":           "Bereinigung der Caches gestartet...",
+	"clean.not_needed":        "Auf allen betroffenen Laufwerken sind mehr als %s frei, nichts zu tun",
+	"clean.nothing":           "Nichts ausgewählt",
+	"clean.mount_low":         "%s: %.2f GB frei, weniger als %s",
+	"clean.threshold_reached": "Schwelle für freien Platz erreicht, die übrigen Caches werden übersprungen",
+	"clean.would":             "Würde bereinigen: %s",
+	"clean.program_errors":    "%s (%d Fehler)",
+	"clean.history_error":     "Verlauf konnte nicht geschrieben werden: %v",
+	"clean.sim_finished":      "Simulation abgeschlossen",
+	"clean.finished":          "Bereinigung abgeschlossen",
+	"clean.cleaned":           "CrunchyCleaner hat bereinigt: %s",
+	"clean.errors":            "Fehler: %s",
+	"clean.summary":           "%s aus %d Caches bereinigt",
+	"clean.summary_errors":    "%s aus %d Caches bereinigt, %d Fehler",
+	"clean.row_paths":         "%4d Pfade",
+	"clean.row_errors":        "%d Fehler",
+	"clean.again":             "[C] erneut bereinigen | [Q] beenden",
+	"clean.cancelled":         "Bereinigung abgebrochen, %d Caches noch nicht bereinigt",
+	"cancel.requested":        "Stoppe nach dem aktuellen Pfad, erneut Strg+C drücken, um sofort zu beenden",
+	"journal.saved":           "Journal in %s geschrieben, 'crunchycleaner resume' schließt die Bereinigung ab",
+	"journal.error":           "Journal konnte nicht geschrieben werden: %v",
+	"journal.read_error":      "Journal kann nicht gelesen werden: %v",
+	"journal.remove_error":    "Journal konnte nicht entfernt werden: %v",
+	"journal.none":            "Keine unterbrochene Bereinigung zum Fortsetzen",
+	"journal.resuming":        "Setze die Bereinigung vom %s fort: %s aus %d Caches erledigt, %d Caches übrig",
+	"delete.skipped":          "%s übersprungen: %v",
+	"delete.cannot_read":      "%s kann nicht gelesen werden: %v",
+	"progress.files":          "%d / %d Dateien",
+	"progress.eta":            "Rest %s",
+
+	// ===== ACCESSIBLE MODE =====
+	"a11y.found":       "%d Cache-Ordner gefunden, %d ausgewählt",
+	"a11y.row":         "%d. %s, %s, %s",
+	"a11y.selected":    "ausgewählt",
+	"a11y.unselected":  "nicht ausgewählt",
+	"a11y.prompt":      "Welchen Eintrag umschalten? 1-%d, a=alle, c=bereinigen, h=Hilfe, q=beenden",
+	"a11y.invalid":     "%q ist keine Eintragsnummer zwischen 1 und %d",
+	"a11y.all_on":      "Alle %d Einträge ausgewählt",
+	"a11y.all_off":     "Alle %d Einträge abgewählt",
+	"a11y.preset":      "Preset %s: %d Einträge ausgewählt",
+	"a11y.preset_none": "Kein Preset, alle Einträge abgewählt",
+	"a11y.path":        "%s, %s, %d Dateien",
+	"a11y.help_items":  "Zahlen wie 3, 1 4 oder 2-5 wählen Einträge aus oder ab",
+	"a11y.help_all":    "a wählt alle Einträge aus, oder ab, wenn schon alle ausgewählt sind",
+	"a11y.help_preset": "p wechselt zum nächsten Preset, p und ein Name wählt dieses Preset",
+	"a11y.help_filter": "f und ein Text zeigt nur Einträge, deren Name oder Kategorie ihn enthält, f allein zeigt alle",
+	"a11y.help_info":   "i und eine Zahl listet die Pfade eines Eintrags",
+	"a11y.help_list":   "l listet die Einträge erneut",
+	"a11y.help_clean":  "c bereinigt die ausgewählten Einträge nach einer Bestätigung",
+	"a11y.help_quit":   "q beendet",
+	"a11y.confirm":     "Jetzt bereinigen? y=ja, d=Probelauf umschalten, n=zurück",
+	"a11y.cancelled":   "Bereinigung abgebrochen",
+	"a11y.again":       "c=erneut scannen und bereinigen, alles andere beendet",
+
+	// ===== NOTIFICATIONS =====
+	"webhook.abandoned":    "Nicht alle Webhooks wurden vor dem Beenden zugestellt",
+	"webhook.encode_error": "Webhook-Daten konnten nicht kodiert werden: %v",
+	"webhook.failed":       "Webhook %s fehlgeschlagen: %v",
+	"metrics.error":        "Metriken konnten nicht geschrieben werden: %v",
+	"audit.error":          "Audit-Log konnte nicht geschrieben werden: %v",
+
+	// ===== PRESETS =====
+	"preset.builtin":        "eingebaut",
+	"preset.config":         "Konfig",
+	"preset.not_in_catalog": "%q ist nicht im Katalog dieses Systems",
+	"preset.saved":          "Preset %q in %s gespeichert",
+	"preset.deleted":        "Preset %q gelöscht",
+
+	// ===== SCHEDULE =====
+	"schedule.cron_fallback": "systemd-Benutzer-Timer nicht verfügbar (%v), weiche auf cron aus",
+	"schedule.installed":     "'%s' geplant (%s)",
+	"schedule.wrote":         "%s geschrieben",
+	"schedule.cron_added":    "crontab-Eintrag hinzugefügt, die Ausgabe geht nach %s",
+	"schedule.task_removed":  "Geplante Aufgabe entfernt",
+	"schedule.timer_removed": "systemd-Benutzer-Timer entfernt",
+	"schedule.cron_error":    "crontab konnte nicht aktualisiert werden: %v",
+	"schedule.cron_removed":  "crontab-Eintrag entfernt",
+	"schedule.none":          "Kein Zeitplan installiert",
+	"daemon.started":         "Daemon gestartet, bereinigt alle %s",
+	"daemon.next":            "Nächster Lauf um %s",
+	"daemon.stopped":         "Angehalten nach %s aus %d Caches, %d Caches übrig",
+	"daemon.not_needed":      "Mehr als %s frei, nichts zu tun",
+	"daemon.nothing":         "Nichts zu bereinigen",
+	"daemon.finished":        "%s aus %d Caches in %s bereinigt",
+	"daemon.finished_errors": "%s aus %d Caches in %s bereinigt, %d Fehler",
+
+	// ===== API =====
+	"api.serving":  "API läuft auf %s (Token aus %s)",
+	"api.started":  "API: Bereinigung %s gestartet (%d Caches, Probelauf: %v)",
+	"api.finished": "API: Bereinigung %s abgeschlossen, %s",
+
+	// ===== HISTORY =====
+	"history.none":           "Noch keine Bereinigungen aufgezeichnet",
+	"history.totals":         "Gesamt",
+	"history.sessions":       "Sitzungen:   %d (%s - %s)",
+	"history.reclaimed":      "Freigegeben: %s",
+	"history.errors":         "Fehler:      %d",
+	"history.recent":         "Letzte Sitzungen",
+	"history.caches":         "%2d Caches",
+	"history.session_errors": "%d Fehler",
+	"history.dry_run":        "(Probelauf)",
+	"history.trend":          "Freigegeben pro %s",
+	"history.day":            "Tag",
+	"history.week":           "Woche",
+	"history.month":          "Monat",
+	"history.growth":         "Am schnellsten wachsende Caches",
+	"history.growth_none":    "Braucht mindestens zwei Bereinigungen desselben Caches",
+	"history.growth_rate":    "%s/Tag  (%d Messungen)",
+}
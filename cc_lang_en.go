@@ -0,0 +1,239 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+// messagesEN is the reference catalog, every key used with tr must be defined here.
+// Messages are fmt formats; translations may reorder arguments with %[n]s.
+var messagesEN = map[string]string{
+	// ===== GENERAL =====
+	"app.init":       "Initializing CrunchyCleaner %s...",
+	"app.exit":       "Exiting CrunchyCleaner...",
+	"app.pause":      "Press [ENTER] to continue...",
+	"app.aborted":    "Aborted by %v",
+	"banner.made_by": "Made by: %s",
+	"banner.version": "Version: %s",
+	"banner.disk":    "Disk-Space: %s / %s",
+	"auto.preset":    "NOTE: Automation active. Scanning and selecting preset %q...",
+	"auto.all":       "NOTE: Automation active. Scanning and selecting all caches...",
+	"scan.spinner":   "Scanning filesystem",
+	"list.empty":     "(empty)",
+	"list.range":     "%d-%d of %d",
+
+	// ===== MENU =====
+	"menu.hint":           "Use %s/%s to navigate | [%s] to select | [%s] to clean | [%s] help",
+	"menu.found":          "Folders found: [%d]",
+	"menu.found_filtered": "Folders found: [%d/%d]",
+	"menu.header":         "%s | [%s] Sort: %s | [%s] Filter: %s",
+	"menu.no_matches":     "No matches",
+	"menu.preset_custom":  "custom",
+	"menu.status":         "Preset: %s | [%s] to switch",
+	"menu.button":         "[ Clean ]",
+	"menu.none_found":     "No cache directories found on your system",
+	"menu.none_left":      "No cache directories left on your system",
+	"sort.catalog":        "catalog",
+	"sort.size":           "size",
+	"sort.name":           "name",
+	"sort.category":       "category",
+	"sort.age":            "age",
+	"detail.title":        "%s: %d paths, %d files",
+	"detail.excluded":     ", %d excluded",
+	"detail.browse":       "[%s] browse",
+	"detail.more":         "... and %d more paths",
+	"detail.files":        "%6d files",
+
+	// ===== HELP =====
+	"help.up":         "Move the cursor up",
+	"help.down":       "Move the cursor down",
+	"help.page_up":    "Move one page up",
+	"help.page_down":  "Move one page down",
+	"help.top":        "Jump to the first entry",
+	"help.bottom":     "Jump to the last entry",
+	"help.toggle":     "Select the entry",
+	"help.toggle_all": "Select all (entries matching the filter)",
+	"help.preset":     "Switch the preset",
+	"help.sort":       "Change the sort order",
+	"help.filter":     "Filter by name or category",
+	"help.browse":     "Browse the paths of the entry, open a folder",
+	"help.parent":     "Go up one folder in the browser",
+	"help.clean":      "Start cleaning",
+	"help.help":       "Show this help",
+	"help.delete":     "Delete the file or folder (explorer)",
+	"help.catalog":    "Add the folder to the catalog (explorer)",
+	"help.close":      "Quit the explorer",
+	"help.esc":        "Clear the filter, go back",
+	"help.quit":       "Quit",
+	"help.title":      "Key bindings",
+	"help.config":     "(change them in the [keys] section of the config)",
+	"help.back":       "Press any key to go back",
+
+	// ===== CONFIRM =====
+	"confirm.title":        "Confirm cleanup",
+	"confirm.needs_rights": "%s needs elevated rights, files of other users will fail",
+	"confirm.running":      "%s: %s is running, close it first so the cache is not in use",
+	"confirm.threshold":    "Only mounts with less than %s free will be cleaned",
+	"confirm.total":        "Total: %d caches, %d paths, %d files, %s",
+	"confirm.row":          "%3d paths %7d files",
+	"confirm.more":         "... and %d more",
+	"confirm.mount":        "(%.2f GB of %.2f GB free now)",
+	"confirm.mode":         "Mode: %s",
+	"confirm.delete":       "Files will be deleted!",
+	"confirm.dry":          "Dry run, nothing will be deleted",
+	"confirm.keys":         "[Y] to clean | [D] to toggle dry run | [N] or [ESC] back to the menu",
+
+	// ===== BROWSER =====
+	"browse.matches":    "matched paths",
+	"browse.keys":       "[%s] open | [%s] back | [%s] include/exclude | [ESC] back to the menu",
+	"browse.status":     "Cleaning %s | Excluded: %d paths (%s)",
+	"browse.unselected": "Not selected, [%s] selects only the entry under the cursor",
+
+	// ===== EXPLORER =====
+	"explore.sizing":         "Sizing %s",
+	"explore.title":          "Explore",
+	"explore.keys":           "[%s] open | [%s] back | [%s] delete | [%s] add to catalog | [%s] quit",
+	"explore.entries":        "%d entries",
+	"explore.range":          "%d-%d of %d entries",
+	"explore.dry":            "DRY RUN",
+	"explore.confirm":        "Delete %s (%s)?",
+	"explore.yes_no":         "[Y/N]",
+	"explore.would":          "Would delete: %s",
+	"explore.dry_status":     "Dry run, nothing deleted: %s",
+	"explore.deleted":        "Deleted %s",
+	"explore.deleted_size":   "Deleted %s (%s)",
+	"explore.deleted_errors": "Deleted %s with %d errors",
+	"explore.in_catalog":     "Already in the catalog as %q",
+	"explore.name":           "Catalog name:",
+	"explore.taken":          "%q is already taken, choose another name",
+	"explore.save_error":     "Could not save: %v",
+	"explore.added":          "Added %q to %s",
+
+	// ===== CLEANUP =====
+	"clean.dry_run_note":      "NOTE: Dry run active. No files will actually be deleted.",
+	"clean.risk":              "You use this tool at your own risk!",
+	"clean.user":              "Username: %s",
+	"clean.selection_error":   "Could not save selection: %v",
+	"clean.started":           "Cleaning caches started...",
+	"clean.not_needed":        "Free space is above %s on every affected mount, nothing to do",
+	"clean.nothing":           "Nothing selected",
+	"clean.mount_low":         "%s: %.2f GB free, below %s",
+	"clean.threshold_reached": "Free space threshold reached, skipping the remaining caches",
+	"clean.would":             "Would clean: %s",
+	"clean.program_errors":    "%s (%d errors)",
+	"clean.history_error":     "Could not write history: %v",
+	"clean.sim_finished":      "Simulation finished",
+	"clean.finished":          "Cleaning finished",
+	"clean.cleaned":           "CrunchyCleaner cleaned: %s",
+	"clean.errors":            "Errors: %s",
+	"clean.summary":           "Cleaned %s from %d caches",
+	"clean.summary_errors":    "Cleaned %s from %d caches, %d errors",
+	"clean.row_paths":         "%4d paths",
+	"clean.row_errors":        "%d errors",
+	"clean.again":             "[C] to clean again | [Q] to quit",
+	"clean.cancelled":         "Cleanup cancelled, %d caches not cleaned yet",
+	"cancel.requested":        "Stopping after the current path, press Ctrl+C again to quit at once",
+	"journal.saved":           "Journal written to %s, run 'crunchycleaner resume' to finish the cleanup",
+	"journal.error":           "Could not write the journal: %v",
+	"journal.read_error":      "Cannot read the journal: %v",
+	"journal.remove_error":    "Could not remove the journal: %v",
+	"journal.none":            "No interrupted cleanup to resume",
+	"journal.resuming":        "Resuming the cleanup of %s: %s from %d caches done, %d caches left",
+	"delete.skipped":          "Skipped %s: %v",
+	"delete.cannot_read":      "Cannot read %s: %v",
+	"progress.files":          "%d / %d files",
+	"progress.eta":            "ETA %s",
+
+	// ===== ACCESSIBLE MODE =====
+	"a11y.found":       "%d cache folders found, %d selected",
+	"a11y.row":         "%d. %s, %s, %s",
+	"a11y.selected":    "selected",
+	"a11y.unselected":  "not selected",
+	"a11y.prompt":      "Toggle which item? 1-%d, a=all, c=clean, h=help, q=quit",
+	"a11y.invalid":     "%q is not an item number between 1 and %d",
+	"a11y.all_on":      "All %d items selected",
+	"a11y.all_off":     "All %d items unselected",
+	"a11y.preset":      "Preset %s: %d items selected",
+	"a11y.preset_none": "No preset, all items unselected",
+	"a11y.path":        "%s, %s, %d files",
+	"a11y.help_items":  "Numbers like 3, 1 4 or 2-5 select or unselect items",
+	"a11y.help_all":    "a selects all items, or unselects them if all are selected",
+	"a11y.help_preset": "p switches to the next preset, p and a name selects that preset",
+	"a11y.help_filter": "f and a text shows only items whose name or category contains it, f alone shows all items",
+	"a11y.help_info":   "i and a number lists the paths of an item",
+	"a11y.help_list":   "l lists the items again",
+	"a11y.help_clean":  "c cleans the selected items after a confirmation",
+	"a11y.help_quit":   "q quits",
+	"a11y.confirm":     "Clean now? y=yes, d=switch dry run, n=back",
+	"a11y.cancelled":   "Cleanup cancelled",
+	"a11y.again":       "c=scan and clean again, anything else quits",
+
+	// ===== NOTIFICATIONS =====
+	"webhook.abandoned":    "Not all webhooks were delivered before exiting",
+	"webhook.encode_error": "Could not encode webhook payload: %v",
+	"webhook.failed":       "Webhook %s failed: %v",
+	"metrics.error":        "Could not write metrics: %v",
+	"audit.error":          "Could not write audit log: %v",
+
+	// ===== PRESETS =====
+	"preset.builtin":        "built-in",
+	"preset.config":         "config",
+	"preset.not_in_catalog": "%q is not in the catalog of this system",
+	"preset.saved":          "Preset %q saved to %s",
+	"preset.deleted":        "Preset %q deleted",
+
+	// ===== SCHEDULE =====
+	"schedule.cron_fallback": "systemd user timer not available (%v), falling back to cron",
+	"schedule.installed":     "Scheduled '%s' (%s)",
+	"schedule.wrote":         "Wrote %s",
+	"schedule.cron_added":    "Added crontab entry, output goes to %s",
+	"schedule.task_removed":  "Scheduled task removed",
+	"schedule.timer_removed": "systemd user timer removed",
+	"schedule.cron_error":    "Could not update crontab: %v",
+	"schedule.cron_removed":  "crontab entry removed",
+	"schedule.none":          "No schedule installed",
+	"daemon.started":         "Daemon started, cleaning every %s",
+	"daemon.next":            "Next run at %s",
+	"daemon.stopped":         "Stopped after cleaning %s from %d caches, %d caches left",
+	"daemon.not_needed":      "Free space is above %s, nothing to do",
+	"daemon.nothing":         "Nothing to clean",
+	"daemon.finished":        "Cleaned %s from %d caches in %s",
+	"daemon.finished_errors": "Cleaned %s from %d caches in %s, %d errors",
+
+	// ===== API =====
+	"api.serving":  "Serving the API on %s (token from %s)",
+	"api.started":  "API: cleanup %s started (%d caches, dry run: %v)",
+	"api.finished": "API: cleanup %s finished, %s",
+
+	// ===== HISTORY =====
+	"history.none":           "No cleanups recorded yet",
+	"history.totals":         "Totals",
+	"history.sessions":       "Sessions:  %d (%s - %s)",
+	"history.reclaimed":      "Reclaimed: %s",
+	"history.errors":         "Errors:    %d",
+	"history.recent":         "Recent sessions",
+	"history.caches":         "%2d caches",
+	"history.session_errors": "%d errors",
+	"history.dry_run":        "(dry run)",
+	"history.trend":          "Reclaimed per %s",
+	"history.day":            "day",
+	"history.week":           "week",
+	"history.month":          "month",
+	"history.growth":         "Fastest growing caches",
+	"history.growth_none":    "Needs at least two cleanups of the same cache",
+	"history.growth_rate":    "%s/day  (%d samples)",
+}
@@ -0,0 +1,49 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCatalogsComplete(t *testing.T) {
+	for _, problem := range checkCatalogs() {
+		t.Error(problem)
+	}
+}
+
+func TestCheckCatalogsReportsKeys(t *testing.T) {
+	broken := map[string]string{"unknown.key": "x", "app.exit": "%d"}
+	for key, msg := range messagesEN {
+		if key != "app.init" && key != "app.exit" {
+			broken[key] = msg
+		}
+	}
+	catalogs["xx"] = broken
+	defer delete(catalogs, "xx")
+
+	got := strings.Join(checkCatalogs(), "\n")
+	for _, want := range []string{`xx: missing "app.init"`, `xx: unknown key "unknown.key"`, `xx: "app.exit" has the verbs`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("checkCatalogs() = %q, want it to contain %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogLevel orders log messages, each sink drops everything below its own minimum
+type LogLevel int
+
+const (
+	LOG_INFO LogLevel = iota
+	LOG_OK
+	LOG_WARN
+	LOG_ERROR
+)
+
+var logLevelNames = []string{"info", "ok", "warn", "error"}
+
+func (l LogLevel) String() string {
+	if int(l) < len(logLevelNames) {
+		return strings.ToUpper(logLevelNames[l])
+	}
+	return "UNKNOWN"
+}
+
+// parseLogLevel accepts info, ok, warn or error; an empty string means info
+func parseLogLevel(s string) (LogLevel, error) {
+	if s == "" {
+		return LOG_INFO, nil
+	}
+	for i, name := range logLevelNames {
+		if strings.EqualFold(s, name) {
+			return LogLevel(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid log level %q (use info, ok, warn or error)", s)
+}
+
+// LogSink receives every log message at or above its level
+type LogSink interface {
+	Level() LogLevel
+	Write(level LogLevel, msg string) error
+	Close() error
+}
+
+// logSinks are the active sinks, the terminal is always the first one
+var (
+	logMu    sync.Mutex
+	logSinks = []LogSink{&terminalSink{min: LOG_INFO}}
+)
+
+// logAt sends a message to every sink
+func logAt(level LogLevel, msg string) {
+	logTo(level, msg, true)
+}
+
+// logQuiet sends a message to every sink except the terminal, for results the UI already shows
+func logQuiet(level LogLevel, msg string) {
+	logTo(level, msg, false)
+}
+
+func logTo(level LogLevel, msg string, terminal bool) {
+	logMu.Lock()
+	defer logMu.Unlock()
+	for _, s := range logSinks {
+		if _, isTerm := s.(*terminalSink); isTerm && !terminal {
+			continue
+		}
+		if level >= s.Level() {
+			s.Write(level, msg)
+		}
+	}
+}
+
+// closeLogs flushes and closes every sink
+func closeLogs() {
+	logMu.Lock()
+	defer logMu.Unlock()
+	for _, s := range logSinks {
+		s.Close()
+	}
+}
+
+// ansiPattern matches color and cursor escape sequences
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;?]*[A-Za-z]")
+
+func stripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+// ========================= TERMINAL =========================
+
+// terminalSink prints the colored [+]/[✓]/[!] lines
+type terminalSink struct {
+	min LogLevel
+}
+
+func (t *terminalSink) Level() LogLevel { return t.min }
+func (t *terminalSink) Close() error    { return nil }
+
+// Write prints the message over the progress line, which is then drawn again below it
+func (t *terminalSink) Write(level LogLevel, msg string) error {
+	var err error
+	switch level {
+	case LOG_INFO:
+		_, err = fmt.Printf(CLEAR_LINE+"%s[+] %s%s\n", CYAN, msg, RC)
+	case LOG_OK:
+		_, err = fmt.Printf(CLEAR_LINE+"%s[✓] %s%s\n", GREEN, msg, RC)
+	default:
+		_, err = fmt.Printf(CLEAR_LINE+"%s[!] %s%s\n", YELLOW, msg, RC)
+	}
+	fmt.Print(progressLine)
+	return err
+}
+
+// progressLine stays at the bottom of the terminal below the log lines, "" if there is none
+var progressLine string
+
+// setProgressLine replaces the progress line, "" removes it
+func setProgressLine(s string) {
+	logMu.Lock()
+	defer logMu.Unlock()
+	progressLine = s
+	fmt.Print(CLEAR_LINE + s)
+}
+
+// ========================= FILE =========================
+
+// fileSink appends plain "time LEVEL message" lines to a file
+type fileSink struct {
+	min LogLevel
+	f   *os.File
+}
+
+func newFileSink(path string, min LogLevel) (*fileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{min: min, f: f}, nil
+}
+
+func (s *fileSink) Level() LogLevel { return s.min }
+func (s *fileSink) Close() error    { return s.f.Close() }
+
+func (s *fileSink) Write(level LogLevel, msg string) error {
+	_, err := fmt.Fprintf(s.f, "%s %-5s %s\n", time.Now().Format(time.RFC3339), level, stripANSI(msg))
+	return err
+}
+
+// ========================= SYSLOG =========================
+
+// syslogFacilities maps facility names to their RFC 5424 codes
+var syslogFacilities = map[string]int{
+	"user": 1, "daemon": 3,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogSink sends RFC 5424 messages to a local syslog socket (usually /dev/log, which journald also reads)
+type syslogSink struct {
+	min      LogLevel
+	network  string // "unixgram" or "unix"; empty tries both
+	addr     string
+	facility int
+	tag      string
+	hostname string
+	conn     net.Conn
+}
+
+// newSyslogSink connects to the syslog socket at addr
+func newSyslogSink(network, addr, facility, tag string, min LogLevel) (*syslogSink, error) {
+	if facility == "" {
+		facility = "user"
+	}
+	code, ok := syslogFacilities[facility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility %q", facility)
+	}
+	if tag == "" {
+		tag = "crunchycleaner"
+	}
+	host, _ := os.Hostname()
+	s := &syslogSink{min: min, network: network, addr: addr, facility: code, tag: syslogName(tag, 48), hostname: syslogName(host, 255)}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// syslogName makes s a valid RFC 5424 HOSTNAME or APP-NAME: printable ASCII without spaces, at most max
+// characters long. Other characters become '_', an empty name is "-" (the nil value).
+func syslogName(s string, max int) string {
+	name := []byte{}
+	for _, r := range s {
+		if len(name) == max {
+			break
+		}
+		if r < '!' || r > '~' {
+			r = '_'
+		}
+		name = append(name, byte(r))
+	}
+	if len(name) == 0 {
+		return "-"
+	}
+	return string(name)
+}
+
+func (s *syslogSink) connect() error {
+	networks := []string{"unixgram", "unix"}
+	if s.network != "" {
+		networks = []string{s.network}
+	}
+	var errs []error
+	for _, n := range networks {
+		conn, err := net.Dial(n, s.addr)
+		if err == nil {
+			s.network, s.conn = n, conn
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (s *syslogSink) Level() LogLevel { return s.min }
+
+func (s *syslogSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
+
+// severity maps our levels to syslog severities (error=3, warning=4, notice=5, info=6)
+func (s *syslogSink) severity(level LogLevel) int {
+	switch level {
+	case LOG_ERROR:
+		return 3
+	case LOG_WARN:
+		return 4
+	case LOG_OK:
+		return 5
+	}
+	return 6
+}
+
+// format builds an RFC 5424 message: <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG
+func (s *syslogSink) format(level LogLevel, msg string) string {
+	return fmt.Sprintf("<%d>1 %s %s %s %d - - %s",
+		s.facility*8+s.severity(level), time.Now().Format(time.RFC3339Nano),
+		s.hostname, s.tag, os.Getpid(), stripANSI(msg))
+}
+
+func (s *syslogSink) Write(level LogLevel, msg string) error {
+	line := s.format(level, msg)
+	if s.network == "unix" {
+		line += "\n" // Stream sockets need a delimiter between messages
+	}
+	if s.conn != nil {
+		if _, err := s.conn.Write([]byte(line)); err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	// The syslog daemon may have been restarted, reconnect once
+	if err := s.connect(); err != nil {
+		return err
+	}
+	_, err := s.conn.Write([]byte(line))
+	return err
+}
+
+// ========================= SETUP =========================
+
+// setupLogging builds the sinks from the config, -log-file and -syslog
+func setupLogging(c *Config, logFile string, syslog bool) error {
+	termLevel, err := parseLogLevel(c.LogTerminalLevel)
+	if err != nil {
+		return fmt.Errorf("[log.terminal] %w", err)
+	}
+	sinks := []LogSink{&terminalSink{min: termLevel}}
+
+	if logFile == "" {
+		logFile = expandHome(c.LogFile)
+	}
+	if logFile != "" {
+		level, err := parseLogLevel(c.LogFileLevel)
+		if err != nil {
+			return fmt.Errorf("[log.file] %w", err)
+		}
+		s, err := newFileSink(logFile, level)
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, s)
+	}
+
+	if syslog || c.Syslog {
+		level, err := parseLogLevel(c.SyslogLevel)
+		if err != nil {
+			return fmt.Errorf("[log.syslog] %w", err)
+		}
+		addr := c.SyslogAddress
+		if addr == "" {
+			addr = "/dev/log"
+		}
+		s, err := newSyslogSink(c.SyslogNetwork, addr, c.SyslogFacility, c.SyslogTag, level)
+		if err != nil {
+			return fmt.Errorf("syslog: %w", err)
+		}
+		sinks = append(sinks, s)
+	}
+
+	logMu.Lock()
+	logSinks = sinks
+	logMu.Unlock()
+	return nil
+}
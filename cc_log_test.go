@@ -0,0 +1,173 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// listenSyslog stands in for /dev/log: a unixgram socket in a temporary directory
+func listenSyslog(t *testing.T) (string, net.PacketConn) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("no unixgram sockets on Windows")
+	}
+	addr := filepath.Join(t.TempDir(), "log.sock")
+	conn, err := net.ListenPacket("unixgram", addr)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return addr, conn
+}
+
+// readSyslog returns the next datagram, or "" if none arrives in time
+func readSyslog(t *testing.T, conn net.PacketConn) string {
+	t.Helper()
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		return ""
+	}
+	return string(buf[:n])
+}
+
+var rfc5424 = regexp.MustCompile(`^<(\d+)>1 (\S+) (\S+) (\S+) (\d+) - - (.*)$`)
+
+func TestSyslogSinkFormat(t *testing.T) {
+	addr, conn := listenSyslog(t)
+	s, err := newSyslogSink("unixgram", addr, "local3", "", LOG_INFO)
+	if err != nil {
+		t.Fatalf("newSyslogSink: %v", err)
+	}
+	defer s.Close()
+
+	tests := []struct {
+		level LogLevel
+		pri   int
+	}{
+		{LOG_ERROR, 19*8 + 3},
+		{LOG_WARN, 19*8 + 4},
+		{LOG_OK, 19*8 + 5},
+		{LOG_INFO, 19*8 + 6},
+	}
+	for _, tt := range tests {
+		if err := s.Write(tt.level, "\033[33mcleaned 5 MB\033[0m"); err != nil {
+			t.Fatalf("Write(%v): %v", tt.level, err)
+		}
+		m := rfc5424.FindStringSubmatch(readSyslog(t, conn))
+		if m == nil {
+			t.Fatalf("%v: message is not RFC 5424", tt.level)
+		}
+		if pri, _ := strconv.Atoi(m[1]); pri != tt.pri {
+			t.Errorf("%v: PRI = %d, want %d", tt.level, pri, tt.pri)
+		}
+		if _, err := time.Parse(time.RFC3339Nano, m[2]); err != nil {
+			t.Errorf("%v: timestamp %q: %v", tt.level, m[2], err)
+		}
+		if m[4] != "crunchycleaner" {
+			t.Errorf("%v: APP-NAME = %q, want crunchycleaner", tt.level, m[4])
+		}
+		if m[5] != strconv.Itoa(os.Getpid()) {
+			t.Errorf("%v: PROCID = %q, want %d", tt.level, m[5], os.Getpid())
+		}
+		if m[6] != "cleaned 5 MB" {
+			t.Errorf("%v: MSG = %q, want the message without colors", tt.level, m[6])
+		}
+	}
+}
+
+func TestSyslogSinkTagAndFacility(t *testing.T) {
+	addr, conn := listenSyslog(t)
+	if _, err := newSyslogSink("unixgram", addr, "kern", "", LOG_INFO); err == nil {
+		t.Errorf("unknown facility accepted")
+	}
+	s, err := newSyslogSink("", addr, "", "cc-test", LOG_INFO)
+	if err != nil {
+		t.Fatalf("newSyslogSink: %v", err)
+	}
+	defer s.Close()
+	s.Write(LOG_INFO, "hello")
+	m := rfc5424.FindStringSubmatch(readSyslog(t, conn))
+	if m == nil || m[1] != "14" || m[4] != "cc-test" {
+		t.Errorf("got %q, want facility user (PRI 14) and APP-NAME cc-test", m)
+	}
+}
+
+func TestSyslogName(t *testing.T) {
+	tests := []struct {
+		in   string
+		max  int
+		want string
+	}{
+		{"crunchycleaner", 48, "crunchycleaner"},
+		{"my cleaner", 48, "my_cleaner"},
+		{"bäckerei\t1", 48, "b_ckerei_1"},
+		{"", 255, "-"},
+		{"abcdef", 4, "abcd"},
+	}
+	for _, tt := range tests {
+		if got := syslogName(tt.in, tt.max); got != tt.want {
+			t.Errorf("syslogName(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+		}
+	}
+
+	addr, conn := listenSyslog(t)
+	s, err := newSyslogSink("", addr, "", "crunchy cleaner", LOG_INFO)
+	if err != nil {
+		t.Fatalf("newSyslogSink: %v", err)
+	}
+	defer s.Close()
+	s.Write(LOG_INFO, "hello")
+	if m := rfc5424.FindStringSubmatch(readSyslog(t, conn)); m == nil || m[4] != "crunchy_cleaner" {
+		t.Errorf("got %q, want APP-NAME crunchy_cleaner", m)
+	}
+}
+
+func TestSyslogSinkLevelFilter(t *testing.T) {
+	addr, conn := listenSyslog(t)
+	s, err := newSyslogSink("unixgram", addr, "user", "", LOG_WARN)
+	if err != nil {
+		t.Fatalf("newSyslogSink: %v", err)
+	}
+	defer s.Close()
+
+	saved := logSinks
+	logSinks = []LogSink{s}
+	defer func() { logSinks = saved }()
+
+	logInfo("info is dropped")
+	logOK("ok is dropped")
+	logWarn("warn passes")
+	if m := rfc5424.FindStringSubmatch(readSyslog(t, conn)); m == nil || m[6] != "warn passes" {
+		t.Errorf("first message = %q, want only the warning", m)
+	}
+	if msg := readSyslog(t, conn); msg != "" {
+		t.Errorf("unexpected message %q", msg)
+	}
+}
@@ -21,18 +21,22 @@ package main
 
 import (
 	"bufio"
+	"cmp"
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"os/signal"
 	"os/user"
 	"path/filepath"
 	"runtime"
-	"strconv"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
+	"unicode/utf8"
 
 	"github.com/eiannone/keyboard"
 )
@@ -41,51 +45,139 @@ import (
 const (
 	CC_VERSION = "2.5.1"
 	COLS       = 62
-	LINES      = 32
+	LINES      = 24 // Assumed terminal height when it can't be read
 	GOOS       = runtime.GOOS
-	YELLOW     = "\033[33m"
-	CYAN       = "\033[36m"
-	GREEN      = "\033[32m"
-	RC         = "\033[0m" // Reset Color
+)
+
+// Process exit codes
+const (
+	EXIT_OK         = 0   // Everything selected was cleaned
+	EXIT_PARTIAL    = 1   // Some paths could not be globbed, sized or deleted
+	EXIT_NOTHING    = 2   // Nothing was selected (or nothing was found)
+	EXIT_PERMISSION = 3   // Every failure was a permission error
+	EXIT_USAGE      = 4   // Invalid arguments or config file
+	EXIT_ABORTED    = 130 // Interrupted by SIGINT/SIGTERM or Ctrl+C
 )
 
 var (
-	origCols, origLines int
 	// CLI Flags
-	Flagversion = flag.Bool("v", false, "Display version information")
-	Flagnoinit  = flag.Bool("t", false, "Skip terminal resizing and environment initialization")
-	Flagdryrun  = flag.Bool("d", false, "Simulation mode without deleting files (for testing)")
-	Flagauto    = flag.Bool("a", false, "Automate cleaning (select all and start immediately)")
+	Flagversion    = flag.Bool("v", false, "Display version information")
+	Flagnoinit     = flag.Bool("t", false, "Skip environment initialization (clear screen, window title)")
+	Flagdryrun     = flag.Bool("d", false, "Simulation mode without deleting files (for testing)")
+	Flagauto       = flag.Bool("a", false, "Automate cleaning (select all and start immediately)")
+	Flagpreset     = flag.String("preset", "", "Select the caches of a named preset (see 'preset list')")
+	Flagiffree     = flag.String("if-free-below", "", "Only clean if free space on an affected mount is below this (e.g. 10GB or 15%)")
+	Flagaudit      = flag.String("audit-log", "", "Append a JSON Lines record for every removed or skipped path to this file")
+	Flaglogfile    = flag.String("log-file", "", "Also write log messages to this file")
+	Flagsyslog     = flag.Bool("syslog", false, "Also send log messages to the local syslog socket (/dev/log)")
+	Flagmetrics    = flag.String("metrics-textfile", "", "Write Prometheus node_exporter textfile metrics to this file")
+	Flagcolor      = flag.String("color", "", "Use colors: auto, always or never (default auto)")
+	Flagtheme      = flag.String("theme", "", "Color theme: default, light, high-contrast or mono")
+	Flagaccessible = flag.Bool("accessible", false, "Screen reader mode: numbered prompts, no redraws or animations")
+	Flaglang       = flag.String("lang", "", "Language of the interface: en or de (default: from the locale)")
+	Flagconfig     = flag.String("config", "", "Path to the config file (default: <user config dir>/crunchycleaner/config.toml)")
 )
 
 // Program represents a target application and its associated cache directories
 type Program struct {
-	Name    string
-	Paths   []string // List of paths (supports wildcards/globbing)
-	Checked bool     // Selection state in the menu
+	Name     string
+	Category string     // System, Browser, Games, Apps, Development or Custom (from the config)
+	Paths    []string   // List of paths (supports wildcards/globbing)
+	Checked  bool       // Selection state in the menu
+	Size     int64      // Bytes found by scanForExisting
+	Matches  []PathInfo // Every path found by scanForExisting, sized without the excluded paths
+	Exclude  []PathInfo // Paths within Matches left out of the cleanup, chosen in the browser
 }
 
-// ========================= HELPER FUNCTIONS =========================
+// PathInfo describes one existing path matched by a Program
+type PathInfo struct {
+	Path   string    `json:"path"`
+	Size   int64     `json:"size"`  // Bytes that would be deleted
+	Files  int       `json:"files"` // Number of files that would be deleted
+	Oldest time.Time `json:"oldest"`
+	Newest time.Time `json:"newest"`
+}
 
-// initApp prepares the terminal environment (Title, Resize, User Info)
-func initApp() {
-	fmt.Printf("Initializing CrunchyCleaner %s...\n", CC_VERSION)
+// CleanError records a single failure while sizing or deleting a path
+type CleanError struct {
+	Stage string // "size" or "delete"
+	Path  string
+	Err   error
+}
 
-	// Get current terminal size
-	if GOOS == "windows" {
-		cmd := exec.Command(
-			"powershell", "-NoProfile", "-Command",
-			"$s=$Host.UI.RawUI.WindowSize; Write-Output \"$($s.Width) $($s.Height)\"",
-		)
+func (e CleanError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Stage, e.Path, e.Err)
+}
 
-		out, _ := cmd.Output()
-		fmt.Sscanf(strings.TrimSpace(string(out)), "%d %d", &origCols, &origLines)
+func (e CleanError) Unwrap() error { return e.Err }
 
-	} else {
-		cmd := exec.Command("sh", "-c", "stty size < /dev/tty")
+// ProgramResult holds the outcome of cleaning a single Program
+type ProgramResult struct {
+	Name   string
+	Paths  int   // Number of matched paths
+	Bytes  int64 // Bytes actually deleted, in a dry run the bytes that would be
+	Errors []CleanError
+}
+
+// CleanResult aggregates the outcome of a whole cleanup session
+type CleanResult struct {
+	Programs  []ProgramResult
+	Start     time.Time
+	Duration  time.Duration
+	DryRun    bool
+	NotNeeded bool      // -if-free-below was set and every mount had enough free space
+	Cancelled bool      // Stopped through CleanOptions.Cancel before everything was cleaned
+	Pending   []Program // What a cancelled cleanup left, paths that were matched already are escaped globs
+}
+
+// Bytes returns the total bytes deleted (or, in a dry run, found) across all Programs
+func (r *CleanResult) Bytes() int64 {
+	var total int64
+	for _, p := range r.Programs {
+		total += p.Bytes
+	}
+	return total
+}
+
+// Errors returns every error collected across all Programs
+func (r *CleanResult) Errors() []CleanError {
+	var errs []CleanError
+	for _, p := range r.Programs {
+		errs = append(errs, p.Errors...)
+	}
+	return errs
+}
 
-		out, _ := cmd.Output()
-		fmt.Sscanf(strings.TrimSpace(string(out)), "%d %d", &origLines, &origCols)
+// ExitCode maps the result to one of the EXIT_* codes
+func (r *CleanResult) ExitCode() int {
+	if r.Cancelled {
+		return EXIT_ABORTED
+	}
+	if r.NotNeeded {
+		return EXIT_OK
+	}
+	if len(r.Programs) == 0 {
+		return EXIT_NOTHING
+	}
+	errs := r.Errors()
+	if len(errs) == 0 {
+		return EXIT_OK
+	}
+	for _, e := range errs {
+		if !errors.Is(e, fs.ErrPermission) {
+			return EXIT_PARTIAL
+		}
+	}
+	return EXIT_PERMISSION
+}
+
+// ========================= HELPER FUNCTIONS =========================
+
+// initApp prepares the terminal environment (Title, User Info)
+func initApp() {
+	fmt.Println(tr("app.init", CC_VERSION))
+	if !termControl {
+		return
 	}
 
 	// Clear screen
@@ -101,51 +193,64 @@ func initApp() {
 
 	}
 	// Fallback use ANSI escape sequences
-	fmt.Print("\033[H\033[2J")
+	fmt.Print(CLEAR_SCREEN)
 
 	// Set Terminal Title via ANSI sequence
 	fmt.Printf("\033]0;CrunchyCleaner %s\007", CC_VERSION)
-	// Resize terminal
-	terminalresize(COLS, LINES)
-	time.Sleep(1 * time.Second)
 }
 
-// cc_exit provides a clean termination of the application
-func cc_exit() {
-	// Close keyboard
-	keyboard.Close()
+// cc_exit provides a clean termination of the application with the given exit code
+func cc_exit(code int) {
+	// Restore the terminal
+	closeInput()
 
 	// Enable cursor
-	fmt.Print("\033[?25h")
+	fmt.Print(SHOW_CURSOR)
 
-	// Restore size
-	if !*Flagnoinit {
-		terminalresize(origCols, origLines)
+	fmt.Printf("\n%s\n", tr("app.exit"))
+	// An unreachable webhook mustn't hold up exiting, and an abort doesn't wait at all
+	if code != EXIT_ABORTED && !waitWebhooks(WEBHOOK_EXIT_WAIT) {
+		logWarn(tr("webhook.abandoned"))
 	}
-
-	fmt.Printf("\nExiting CrunchyCleaner...\n")
-	os.Exit(0)
+	closeLogs()
+	os.Exit(code)
 }
 
 func pause() {
-	fmt.Printf("\nPress [ENTER] to continue...")
+	fmt.Printf("\n%s", tr("app.pause"))
 	bufio.NewReader(os.Stdin).ReadBytes('\n')
 }
 
 // line draws a formatted horizontal separator
 func line() {
-	fmt.Printf("%s#%s~%s\n", YELLOW, strings.Repeat("-", COLS-2), RC)
+	fmt.Println(separator())
+}
+
+// separator returns a horizontal line as wide as the layout, or the terminal if that is narrower
+func separator() string {
+	if *Flagaccessible {
+		return ""
+	}
+	cols, _ := screenSize()
+	return fmt.Sprintf("%s#%s~%s", YELLOW, strings.Repeat("-", max(min(COLS, cols)-2, 0)), RC)
 }
 
 // spinner visualizes background tasks and cleans up properly
 func spinner(text string, stop chan bool, ack chan bool) {
+	if !termControl {
+		// No animation, just say what is going on
+		fmt.Printf("%s...\n", text)
+		<-stop
+		ack <- true
+		return
+	}
 	frames := []string{"|", "/", "-", "\\"}
 	i := 0
 	for {
 		select {
 		case <-stop:
 			// Clear the line and move cursor to start
-			fmt.Print("\r\033[K")
+			fmt.Print(CLEAR_LINE)
 			// Signal back to main that we are done
 			ack <- true
 			return
@@ -157,27 +262,23 @@ func spinner(text string, stop chan bool, ack chan bool) {
 	}
 }
 
-func terminalresize(w int, h int) {
-	// OS-specific Terminal Resizing
-	if GOOS == "windows" {
-		psCmd := fmt.Sprintf(
-			`$w=(Get-Host).UI.RawUI; 
-				$newSize=New-Object System.Management.Automation.Host.Size(%d,%d); 
-				$newBuffer=New-Object System.Management.Automation.Host.Size(%d,999); 
-				$w.BufferSize=$newBuffer; 
-				$w.WindowSize=$newSize`,
-			w, h, w,
-		)
-		exec.Command("powershell", "-NoProfile", "-Command", psCmd).Run()
-	}
-
-	// Generic ANSI resize fallback for modern terminals
-	fmt.Printf("\033[8;%d;%dt", h, w)
-}
-
 // ========================= PROGRAMS =========================
 
+// getPrograms returns the built-in catalog followed by the custom entries from the config.
+// A custom entry with the name of a built-in one replaces it.
 func getPrograms() []Program {
+	programs := builtinPrograms()
+	for _, c := range cfg.Catalog {
+		if i := slices.IndexFunc(programs, func(p Program) bool { return strings.EqualFold(p.Name, c.Name) }); i >= 0 {
+			programs[i] = c
+		} else {
+			programs = append(programs, c)
+		}
+	}
+	return programs
+}
+
+func builtinPrograms() []Program {
 	if runtime.GOOS == "windows" {
 		// Windows
 		home, _ := os.UserHomeDir()
@@ -187,78 +288,78 @@ func getPrograms() []Program {
 		programFiles := os.Getenv("ProgramFiles")
 		winDir := os.Getenv("WINDIR")
 		return []Program{
-			{"System Logs (Admin)", []string{
+			{Name: "System Logs (Admin)", Category: "System", Paths: []string{
 				filepath.Join(winDir, "Panther"),
 				filepath.Join(winDir, "Logs"),
-			}, false},
-			{"Font Cache (Admin)", []string{filepath.Join(winDir, "ServiceProfiles/LocalService/AppData/Local/FontCache")}, false},
-			{"System Temp Folders (Admin)", []string{filepath.Join(winDir, "Temp")}, false},
-			{"Update Logs (Admin)", []string{filepath.Join(winDir, "SoftwareDistribution/Download")}, false},
-			{"User Temp Folder", []string{filepath.Join(localAppData, "Temp")}, false},
-			{"Thumbnail Cache", []string{filepath.Join(localAppData, "Microsoft/Windows/Explorer")}, false},
-			{"Firefox Cache", []string{
+			}},
+			{Name: "Font Cache (Admin)", Category: "System", Paths: []string{filepath.Join(winDir, "ServiceProfiles/LocalService/AppData/Local/FontCache")}},
+			{Name: "System Temp Folders (Admin)", Category: "System", Paths: []string{filepath.Join(winDir, "Temp")}},
+			{Name: "Update Logs (Admin)", Category: "System", Paths: []string{filepath.Join(winDir, "SoftwareDistribution/Download")}},
+			{Name: "User Temp Folder", Category: "System", Paths: []string{filepath.Join(localAppData, "Temp")}},
+			{Name: "Thumbnail Cache", Category: "System", Paths: []string{filepath.Join(localAppData, "Microsoft/Windows/Explorer")}},
+			{Name: "Firefox Cache", Category: "Browser", Paths: []string{
 				filepath.Join(localAppData, "Mozilla/Firefox/Profiles/*/cache2"),
 				filepath.Join(localAppData, "Mozilla/Firefox/Profiles/*/jumpListCache"),
 				filepath.Join(appData, "Mozilla/Firefox/Profiles/*/shader-cache"),
-			}, false},
-			{"Chrome Cache", []string{
+			}},
+			{Name: "Chrome Cache", Category: "Browser", Paths: []string{
 				filepath.Join(localAppData, "Google/Chrome/User Data/Default/Cache"),
 				filepath.Join(localAppData, "Google/Chrome/User Data/Default/Code Cache"),
 				filepath.Join(localAppData, "Google/Chrome/User Data/*/Cache"),
 				filepath.Join(localAppData, "Google/Chrome/User Data/Default/Media Cache"),
-			}, false},
-			{"Edge Cache", []string{
+			}},
+			{Name: "Edge Cache", Category: "Browser", Paths: []string{
 				filepath.Join(localAppData, "Microsoft/Edge/User Data/Default/Cache"),
 				filepath.Join(localAppData, "Microsoft/Edge/User Data/*/Cache"),
 				filepath.Join(localAppData, "Microsoft/Edge/User Data/Default/Media Cache"),
-			}, false},
-			{"Brave Cache", []string{
+			}},
+			{Name: "Brave Cache", Category: "Browser", Paths: []string{
 				filepath.Join(localAppData, "BraveSoftware/Brave-Browser/User Data/Default/Cache"),
 				filepath.Join(localAppData, "BraveSoftware/Brave-Browser/User Data/*/Cache"),
 				filepath.Join(localAppData, "BraveSoftware/Brave-Browser/User Data/Default/Media Cache"),
-			}, false},
-			{"Opera Cache", []string{
+			}},
+			{Name: "Opera Cache", Category: "Browser", Paths: []string{
 				filepath.Join(localAppData, "Opera Software/Opera Stable/Cache"),
 				filepath.Join(localAppData, "Opera Software/Opera Stable/Code Cache"),
-			}, false},
-			{"Thunderbird Cache", []string{
+			}},
+			{Name: "Thunderbird Cache", Category: "Apps", Paths: []string{
 				filepath.Join(localAppData, "Thunderbird/Profiles/*/cache2"),
-			}, false},
-			{"Steam Cache", []string{
+			}},
+			{Name: "Steam Cache", Category: "Games", Paths: []string{
 				filepath.Join(programFilesX86, "Steam/appcache"),
 				filepath.Join(programFiles, "Steam/appcache"),
 				filepath.Join(localAppData, "Steam/htmlcache"),
-			}, false},
-			{"Epic Games Cache", []string{filepath.Join(localAppData, "EpicGamesLauncher/Saved/webcache")}, false},
-			{"Discord Cache", []string{
+			}},
+			{Name: "Epic Games Cache", Category: "Games", Paths: []string{filepath.Join(localAppData, "EpicGamesLauncher/Saved/webcache")}},
+			{Name: "Discord Cache", Category: "Apps", Paths: []string{
 				filepath.Join(appData, "discord/Cache"),
 				filepath.Join(appData, "discord/Code Cache"),
 				filepath.Join(appData, "discord/GPUCache"),
-			}, false},
-			{"Telegram Cache", []string{filepath.Join(appData, "Telegram Desktop/tdata/user_data/cache")}, false},
-			{"Spotify Cache", []string{filepath.Join(localAppData, "Spotify/Storage")}, false},
-			{"VS Code Cache", []string{
+			}},
+			{Name: "Telegram Cache", Category: "Apps", Paths: []string{filepath.Join(appData, "Telegram Desktop/tdata/user_data/cache")}},
+			{Name: "Spotify Cache", Category: "Apps", Paths: []string{filepath.Join(localAppData, "Spotify/Storage")}},
+			{Name: "VS Code Cache", Category: "Development", Paths: []string{
 				filepath.Join(appData, "Code/Cache"),
 				filepath.Join(appData, "Code/CachedData"),
 				filepath.Join(appData, "Code/CachedExtensionVSIXs"),
 				filepath.Join(appData, "Code/User/workspaceStorage"),
 				filepath.Join(appData, "Code/GPUCache"),
-			}, false},
-			{"Shader Cache", []string{
+			}},
+			{Name: "Shader Cache", Category: "System", Paths: []string{
 				filepath.Join(localAppData, "D3DSCache"),
 				filepath.Join(localAppData, "NVIDIA/GLCache"),
-			}, false},
-			{"Go Build Cache", []string{filepath.Join(localAppData, "go-build")}, false},
-			{"Pip Cache", []string{filepath.Join(localAppData, "pip/Cache")}, false},
-			{"NPM Cache", []string{filepath.Join(appData, "npm-cache/_cacache")}, false},
-			{"Yarn Cache", []string{
+			}},
+			{Name: "Go Build Cache", Category: "Development", Paths: []string{filepath.Join(localAppData, "go-build")}},
+			{Name: "Pip Cache", Category: "Development", Paths: []string{filepath.Join(localAppData, "pip/Cache")}},
+			{Name: "NPM Cache", Category: "Development", Paths: []string{filepath.Join(appData, "npm-cache/_cacache")}},
+			{Name: "Yarn Cache", Category: "Development", Paths: []string{
 				filepath.Join(localAppData, "Yarn/Cache"),
 				filepath.Join(appData, "Yarn/Cache"),
-			}, false},
-			{"Cargo Cache", []string{
+			}},
+			{Name: "Cargo Cache", Category: "Development", Paths: []string{
 				filepath.Join(home, ".cargo/registry/cache"),
 				filepath.Join(home, ".cargo/git/db"),
-			}, false},
+			}},
 		}
 	} else {
 		// Linux
@@ -266,72 +367,72 @@ func getPrograms() []Program {
 		cache := ".cache/"
 		flatpak := ".var/app/"
 		return []Program{
-			{"System Logs (Root)", []string{"/var/log/*.log"}, false},
-			{"System Temp Folders (Root)", []string{"/tmp"}, false},
-			{"Thumbnail Cache", []string{filepath.Join(home, cache, "thumbnails")}, false},
-			{"Firefox Cache", []string{
+			{Name: "System Logs (Root)", Category: "System", Paths: []string{"/var/log/*.log"}},
+			{Name: "System Temp Folders (Root)", Category: "System", Paths: []string{"/tmp"}},
+			{Name: "Thumbnail Cache", Category: "System", Paths: []string{filepath.Join(home, cache, "thumbnails")}},
+			{Name: "Firefox Cache", Category: "Browser", Paths: []string{
 				filepath.Join(home, cache, "mozilla/firefox/*/cache2"),
 				filepath.Join(home, flatpak, "org.mozilla.firefox/cache/mozilla/firefox/*/cache2"),
-			}, false},
-			{"Chromium Cache", []string{
+			}},
+			{Name: "Chromium Cache", Category: "Browser", Paths: []string{
 				filepath.Join(home, cache, "chromium/*/Cache"),
 				filepath.Join(home, cache, "chromium/*/Code Cache"),
 				filepath.Join(home, flatpak, "com.google.Chrome/cache/chromium/*/Cache"),
 				filepath.Join(home, flatpak, "com.google.Chrome/cache/chromium/*/CodeCache"),
-			}, false},
-			{"Edge Cache", []string{
+			}},
+			{Name: "Edge Cache", Category: "Browser", Paths: []string{
 				filepath.Join(home, cache, "microsoft-edge/*/Cache"),
 				filepath.Join(home, cache, "microsoft-edge/*/Code Cache"),
 				filepath.Join(home, flatpak, "com.microsoft.Edge/cache/microsoft-edge/*/Cache"),
 				filepath.Join(home, flatpak, "com.microsoft.Edge/cache/microsoft-edge/*/CodeCache"),
-			}, false},
-			{"Brave Cache", []string{
+			}},
+			{Name: "Brave Cache", Category: "Browser", Paths: []string{
 				filepath.Join(home, cache, "BraveSoftware/Brave-Browser/*/Cache"),
 				filepath.Join(home, cache, "BraveSoftware/Brave-Browser/*/Code Cache"),
 				filepath.Join(home, flatpak, "com.brave.Browser/cache/Brave-Browser/*/Cache"),
 				filepath.Join(home, flatpak, "com.brave.Browser/cache/Brave-Browser/*/Code Cache"),
-			}, false},
-			{"Opera Cache", []string{
+			}},
+			{Name: "Opera Cache", Category: "Browser", Paths: []string{
 				filepath.Join(home, cache, "opera/Cache"),
 				filepath.Join(home, ".config/opera/Cache"),
 				filepath.Join(home, flatpak, "com.opera.Opera/cache/opera/Cache"),
 				filepath.Join(home, flatpak, ".com.opera.Opera/config/opera/Cache"),
-			}, false},
-			{"Thunderbird Cache", []string{
+			}},
+			{Name: "Thunderbird Cache", Category: "Apps", Paths: []string{
 				filepath.Join(home, cache, "thunderbird/*/cache2"),
 				filepath.Join(home, flatpak, "org.mozilla.Thunderbird/cache/mozilla/Thunderbird/*/cache2"),
-			}, false},
-			{"Steam Cache", []string{
+			}},
+			{Name: "Steam Cache", Category: "Games", Paths: []string{
 				filepath.Join(home, ".steam/steam/appcache"),
 				filepath.Join(home, ".local/share/Steam/appcache"),
 				filepath.Join(home, ".local/share/Steam/config/htmlcache"),
 				filepath.Join(home, flatpak, "com.valvesoftware.Steam/steam/steam/appcache"),
 				filepath.Join(home, flatpak, "com.valvesoftware.Steam/.local/share/Steam/appcache"),
 				filepath.Join(home, flatpak, "com.valvesoftware.Steam/.local/share/Steam/config/htmlcache"),
-			}, false},
-			{"Epic Games (Heroic/Lutris) Cache", []string{
+			}},
+			{Name: "Epic Games (Heroic/Lutris) Cache", Category: "Games", Paths: []string{
 				filepath.Join(home, ".config/heroic/WebCache"),
 				filepath.Join(home, ".local/share/lutris/runtime"),
 				filepath.Join(home, flatpak, "com.heroicgameslauncher.hgl/config/heroic/WebCache"),
 				filepath.Join(home, flatpak, "com.heroicgameslauncher.hgl/.local/share/lutris/runtime"),
-			}, false},
-			{"Discord Cache", []string{
+			}},
+			{Name: "Discord Cache", Category: "Apps", Paths: []string{
 				filepath.Join(home, ".config/discord/Cache"),
 				filepath.Join(home, ".config/discord/Code Cache"),
 				filepath.Join(home, ".config/discord/GPUCache"),
 				filepath.Join(home, flatpak, "com.discordapp.Discord/config/discord/Cache"),
 				filepath.Join(home, flatpak, "com.discordapp.Discord/config/discord/Code Cache"),
 				filepath.Join(home, flatpak, "com.discordapp.Discord/config/discord/GPUCache"),
-			}, false},
-			{"Telegram Cache", []string{filepath.Join(
+			}},
+			{Name: "Telegram Cache", Category: "Apps", Paths: []string{filepath.Join(
 				home, ".local/share/TelegramDesktop/tdata/user_data/cache"),
 				filepath.Join(home, flatpak, "org.telegram.desktop/data/TelegramDesktop/tdata/user_data/cache"),
-			}, false},
-			{"Spotify Cache", []string{
+			}},
+			{Name: "Spotify Cache", Category: "Apps", Paths: []string{
 				filepath.Join(home, cache, "spotify"),
 				filepath.Join(home, flatpak, "com.spotify.Client/cache/spotify"),
-			}, false},
-			{"VS Code Cache", []string{
+			}},
+			{Name: "VS Code Cache", Category: "Development", Paths: []string{
 				filepath.Join(home, ".config/Code/Cache"),
 				filepath.Join(home, ".config/Code/CachedData"),
 				filepath.Join(home, ".config/Code/GPUCache"),
@@ -340,16 +441,16 @@ func getPrograms() []Program {
 				filepath.Join(home, flatpak, "com.visualstudio.code/config/Code/CachedData"),
 				filepath.Join(home, flatpak, "com.visualstudio.code/config/Code/GPUCache"),
 				filepath.Join(home, flatpak, "com.visualstudio.code/config/Code/User/workspaceStorage"),
-			}, false},
-			{"Shader Cache", []string{
+			}},
+			{Name: "Shader Cache", Category: "System", Paths: []string{
 				filepath.Join(home, cache, "mesa_shader_cache"),
 				filepath.Join(home, cache, "nvidia/GLCache"),
-			}, false},
-			{"Go Build Cache", []string{filepath.Join(home, cache, "go-build")}, false},
-			{"Pip Cache", []string{filepath.Join(home, cache, "pip")}, false},
-			{"NPM Cache", []string{filepath.Join(home, ".npm/_cacache")}, false},
-			{"Yarn Cache", []string{filepath.Join(home, cache, "yarn")}, false},
-			{"Cargo Cache", []string{filepath.Join(home, ".cargo/registry/cache")}, false},
+			}},
+			{Name: "Go Build Cache", Category: "Development", Paths: []string{filepath.Join(home, cache, "go-build")}},
+			{Name: "Pip Cache", Category: "Development", Paths: []string{filepath.Join(home, cache, "pip")}},
+			{Name: "NPM Cache", Category: "Development", Paths: []string{filepath.Join(home, ".npm/_cacache")}},
+			{Name: "Yarn Cache", Category: "Development", Paths: []string{filepath.Join(home, cache, "yarn")}},
+			{Name: "Cargo Cache", Category: "Development", Paths: []string{filepath.Join(home, ".cargo/registry/cache")}},
 		}
 	}
 }
@@ -359,28 +460,10 @@ func getDiskMetrics() (freeGB float64, totalStr, freeStr string) {
 	totalStr, freeStr = "N/A", "N/A"
 	const GB = 1024 * 1024 * 1024
 
-	cmd := "sh -c \"df -B1 --output=size,avail / | tail -1\""
-	if GOOS == "windows" {
-		cmd = "powershell -NoProfile -Command \"(Get-PSDrive C).Used, (Get-PSDrive C).Free\""
-	}
-
-	out, err := exec.Command("sh", "-c", cmd).Output() // Note: Windows braucht hier exec.Command("powershell", ...)
-	if GOOS == "windows" {
-		out, err = exec.Command("powershell", "-C", cmd).Output()
-	}
-
-	parts := strings.Fields(string(out))
-	if err == nil && len(parts) >= 2 {
-		v1, _ := strconv.ParseFloat(parts[0], 64)
-		v2, _ := strconv.ParseFloat(parts[1], 64)
-
-		if GOOS == "windows" {
-			freeGB = v2 / GB
-			totalStr = fmt.Sprintf("%.2f GB", (v1+v2)/GB)
-		} else {
-			freeGB = v2 / GB
-			totalStr = fmt.Sprintf("%.2f GB", v1/GB)
-		}
+	total, free, err := diskUsage(systemRoot())
+	if err == nil {
+		freeGB = float64(free) / GB
+		totalStr = fmt.Sprintf("%.2f GB", float64(total)/GB)
 		freeStr = fmt.Sprintf("%.2f GB", freeGB)
 	}
 	return
@@ -392,22 +475,52 @@ func formatMB(bytes int64) string {
 	return fmt.Sprintf("%.2f MB", mb)
 }
 
-// getDirSize remains the same (calculating in bytes first for precision)
-func getDirSize(path string) int64 {
-	var size int64
-	matches, _ := filepath.Glob(expandHome(path))
-	for _, m := range matches {
-		filepath.Walk(m, func(_ string, info os.FileInfo, err error) error {
-			if err != nil {
-				return nil
-			}
-			if !info.IsDir() {
-				size += info.Size()
+// statPath walks a single existing path and describes the files in it that would actually be deleted,
+// so files protected by the retention rules or below an excluded path are not counted.
+// Errors are collected instead of aborting, so a single unreadable folder doesn't hide the rest.
+func statPath(path string, exclude []PathInfo) (PathInfo, []CleanError) {
+	pi := PathInfo{Path: path}
+	var errs []CleanError
+	filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			errs = append(errs, CleanError{"size", p, err})
+			return nil
+		}
+		if isExcluded(p, exclude) {
+			if info.IsDir() {
+				return filepath.SkipDir
 			}
 			return nil
-		})
+		}
+		if info.IsDir() || retained(p, info) {
+			return nil
+		}
+		pi.Size += info.Size()
+		pi.Files++
+		if mt := info.ModTime(); pi.Files == 1 || mt.Before(pi.Oldest) {
+			pi.Oldest = mt
+		}
+		if mt := info.ModTime(); mt.After(pi.Newest) {
+			pi.Newest = mt
+		}
+		return nil
+	})
+	return pi, errs
+}
+
+// currentUsername returns the name of the current system user, or "unknown"
+func currentUsername() string {
+	usr, err := user.Current()
+	if err != nil {
+		return "unknown"
 	}
-	return size
+	name := usr.Username
+	// Strip domain/machine name prefix on Windows
+	if GOOS == "windows" && strings.Contains(name, "\\") {
+		parts := strings.Split(name, "\\")
+		name = parts[len(parts)-1]
+	}
+	return name
 }
 
 // expandHome resolves the shorthand '~/ ' to the absolute user home directory
@@ -422,8 +535,22 @@ func expandHome(path string) string {
 // ========================= MENU UI LOGIC =========================
 
 func showBanner() {
+	if *Flagaccessible {
+		// The logo means nothing to a screen reader
+		_, total, free := getDiskMetrics()
+		fmt.Printf("CrunchyCleaner %s, %s\n", CC_VERSION, tr("banner.disk", free, total))
+		return
+	}
+	for _, l := range bannerLines() {
+		fmt.Println(l)
+	}
+	line()
+}
+
+// bannerLines returns the logo with version and disk space, one string per line
+func bannerLines() []string {
 	_, total, free := getDiskMetrics()
-	fmt.Printf(`%s  ____________________     .-.
+	banner := fmt.Sprintf(`%s  ____________________     .-.
  |   |  |       __ |  \    |_|
  |   |  |      |  ||  |    | |
  |   |  |      |__||  |    |=|
@@ -433,40 +560,140 @@ func showBanner() {
  |  |______________|  | |||||||||
  |  |              |  |
  |  |              |  | %sCrunchyCleaner%s
- |  |              |  | Made by: Knuspii, (M)
- |[]|              |[]| Version: %s
- |__|______________|__| Disk-Space: %s / %s%s
-`, YELLOW, RC, YELLOW, CC_VERSION, free, total, RC)
-	line()
+ |  |              |  | %s
+ |[]|              |[]| %s
+ |__|______________|__| %s%s`, YELLOW, RC, YELLOW,
+		tr("banner.made_by", "Knuspii, (M)"), tr("banner.version", CC_VERSION), tr("banner.disk", free, total), RC)
+	lines := strings.Split(banner, "\n")
+	// Every line gets its own color, so a cut line doesn't leave the rest of the screen yellow
+	for i := range lines {
+		lines[i] = YELLOW + lines[i] + RC
+	}
+	return lines
 }
 
-func logInfo(msg string) { fmt.Printf("%s[+] %s%s\n", CYAN, msg, RC) }
-func logOK(msg string)   { fmt.Printf("%s[✓] %s%s\n", GREEN, msg, RC) }
-func logWarn(msg string) { fmt.Printf("%s[!] %s%s\n", YELLOW, msg, RC) }
-
-// renderMenu draws the interactive selection list
-func renderMenu(existing []Program, idx int, fullRedraw bool) {
+func logInfo(msg string) { logAt(LOG_INFO, msg) }
+func logOK(msg string)   { logAt(LOG_OK, msg) }
+func logWarn(msg string) { logAt(LOG_WARN, msg) }
+
+// renderMenu draws the visible part of the selection list, the status lines and the detail pane.
+// A full redraw clears the screen and lays out banner, list and pane for the current terminal size,
+// dropping the banner and then the pane when the list would get too short.
+// Otherwise only list and pane are redrawn in place.
+func renderMenu(m *menuState, fullRedraw bool) {
+	cols, rows := screenSize()
+	view := &m.view
+	n := len(m.visible)
 	if fullRedraw {
-		showBanner()
-		fmt.Printf("Use ↑/↓ or W/S to navigate | [ENTER] to select | [C] to clean\n")
-		fmt.Printf("Folders found: [%d]\n", len(existing))
+		var header []string
+		banner := bannerLines()
+		// Help line, list header, status line and one spare row so the last newline doesn't scroll
+		listRows := rows - 4
+		view.detail = 0
+		if listRows-DETAIL_ROWS >= min(len(m.programs), MIN_LIST_ROWS) {
+			view.detail = DETAIL_ROWS
+			listRows -= DETAIL_ROWS
+		}
+		if cols >= COLS && listRows-len(banner)-1 >= min(len(m.programs), MIN_LIST_ROWS) {
+			header = append(banner, separator())
+			listRows -= len(header)
+		}
+		header = append(header, tr("menu.hint",
+			bindings.label(ACT_UP), bindings.label(ACT_DOWN), bindings.label(ACT_TOGGLE), bindings.label(ACT_CLEAN), bindings.label(ACT_HELP)))
+
+		fmt.Print(CLEAR_SCREEN)
+		for _, l := range header {
+			fmt.Printf("%s\n", fitWidth(l, cols))
+		}
+		view.row = len(header) + 1
+		view.height = listRows
 	}
+	view.follow(m.idx, n)
 
-	// Render each detected program entry
-	for i := range existing {
+	// Jump to the list header, it changes with sorting and filtering
+	fmt.Print(cursorTo(view.row))
+	found := tr("menu.found", len(m.programs))
+	if m.filter != "" || m.filtering {
+		found = tr("menu.found_filtered", n, len(m.programs))
+	}
+	filter := m.filter
+	if m.filtering {
+		filter += "_"
+	}
+	fmt.Printf(CLEAR_LINE+"%s\n", fitWidth(tr("menu.header",
+		found, bindings.label(ACT_SORT), YELLOW+tr("sort."+m.sort)+RC, bindings.label(ACT_FILTER), YELLOW+filter+RC), cols))
+
+	// Render each visible program entry
+	for i := view.top; i < view.top+view.height; i++ {
+		if i >= n {
+			if i == 0 {
+				fmt.Printf(CLEAR_LINE+"    %s\n", tr("menu.no_matches"))
+			}
+			break
+		}
+		p := m.programs[m.visible[i]]
 		cursor := "    "
 		// Highlight the currently selected entry
-		if i == idx {
+		if i == m.idx {
 			cursor = YELLOW + "  >_" + RC
 		}
 		// Checkbox indicator for selection state
 		check := "[ ]"
-		if existing[i].Checked {
+		if p.Checked && len(p.Exclude) > 0 {
+			check = "[" + GREEN + "~" + RC + "]" // Some sub-folders are excluded
+		} else if p.Checked {
 			check = "[" + GREEN + "X" + RC + "]"
 		}
 		// Clear the current line and print the menu entry
-		fmt.Printf("\r\033[K%s%s %s\n", cursor, check, existing[i].Name)
+		entry := fmt.Sprintf("%s%s %-30s %s(%s)%s", cursor, check, p.Name, YELLOW, formatMB(p.Size), RC)
+		fmt.Printf(CLEAR_LINE+"%s\n", fitWidth(entry, cols))
+	}
+
+	preset := m.preset
+	if preset == "" {
+		preset = tr("menu.preset_custom")
+	}
+	status := tr("menu.status", YELLOW+preset+RC, bindings.label(ACT_PRESET))
+	view.button = 0
+	if mouseEnabled {
+		status = GREEN + tr("menu.button") + RC + " " + status
+		view.button = view.row + 1 + max(view.end(n)-view.top, 1)
+	}
+	if view.height < n {
+		status += fmt.Sprintf(" | %d-%d of %d", view.top+1, view.end(n), n)
 	}
+	fmt.Printf(CLEAR_LINE+"%s\n", fitWidth(status, cols))
+	if p := m.current(); view.detail > 0 && p != nil {
+		for _, l := range detailLines(*p, view.detail) {
+			fmt.Printf(CLEAR_LINE+"%s\n", fitWidth(l, cols))
+		}
+	}
+	// Clear everything below, in case the list or pane got shorter
+	fmt.Print(CLEAR_BELOW)
+}
+
+// detailLines describes the paths a Program would delete in at most n lines
+func detailLines(p Program, n int) []string {
+	title := tr("detail.title", CYAN+p.Name+RC, len(p.Matches), p.files())
+	if len(p.Exclude) > 0 {
+		title += tr("detail.excluded", len(p.Exclude))
+	}
+	lines := []string{title + " | " + tr("detail.browse", bindings.label(ACT_BROWSE))}
+	day := func(t time.Time) string {
+		if t.IsZero() {
+			return "----------"
+		}
+		return t.Format("2006-01-02")
+	}
+	for i, m := range p.Matches {
+		if len(lines) == n-1 && i < len(p.Matches)-1 {
+			lines = append(lines, "  "+tr("detail.more", len(p.Matches)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  %s%10s%s %s  %s..%s  %s",
+			YELLOW, formatMB(m.Size), RC, tr("detail.files", m.Files), day(m.Oldest), day(m.Newest), m.Path))
+	}
+	return lines
 }
 
 // function to scan which programs actually exist on the disk
@@ -474,226 +701,658 @@ func scanForExisting() []Program {
 	allPrograms := getPrograms()
 	existing := []Program{}
 	for _, p := range allPrograms {
-		found := false
-		var totalSize int64
-		for _, path := range p.Paths {
-			matches, _ := filepath.Glob(expandHome(path))
-			if len(matches) > 0 {
-				found = true
-				totalSize += getDirSize(path)
-			}
-		}
-		if found {
-			// We format the name here so it's ready for both UI and Logs
-			p.Name = fmt.Sprintf("%-30s %s(%s)%s", p.Name, YELLOW, formatMB(totalSize), RC)
+		p.scan()
+		if len(p.Matches) > 0 {
 			existing = append(existing, p)
 		}
 	}
 	return existing
 }
 
-// handleMenu manages user input for navigation and selection
-func handleMenu() {
-	// Initial scan of the filesystem to find existing directories
-	stop := make(chan bool)
-	ack := make(chan bool)
-	go spinner("Scanning filesystem", stop, ack)
-	time.Sleep(1 * time.Second)
+// scan fills Matches and Size from the paths of p, leaving out the excluded ones
+func (p *Program) scan() {
+	for _, path := range p.Paths {
+		matches, _ := filepath.Glob(expandHome(path))
+		for _, m := range matches {
+			if isExcluded(m, p.Exclude) {
+				continue
+			}
+			info, _ := statPath(m, p.Exclude)
+			p.Matches = append(p.Matches, info)
+			p.Size += info.Size
+		}
+	}
+}
 
-	existing := scanForExisting()
+// selectForAuto checks the Programs of the given preset, or every Program without one
+func selectForAuto(existing []Program, preset string) error {
+	if preset != "" {
+		return applyPreset(existing, preset)
+	}
+	for i := range existing {
+		existing[i].Checked = true
+	}
+	return nil
+}
 
-	stop <- true // Tell spinner to stop
-	<-ack        // WAIT for spinner to clear the line
+// handleMenu manages user input for navigation and selection.
+// If preset is set, its Programs are checked instead of the configured defaults.
+func handleMenu(preset string) {
+	// Initial scan of the filesystem to find existing directories
+	existing := scanWithSpinner()
 
 	// Abort if nothing was detected
 	if len(existing) == 0 {
-		fmt.Printf("\nNo cache directories found on your system")
+		fmt.Printf("\n%s", tr("menu.none_found"))
 		pause()
-		return
+		cc_exit(EXIT_NOTHING)
+	}
+	if preset != "" {
+		if err := applyPreset(existing, preset); err != nil {
+			fmt.Printf("\n%s", err)
+			pause()
+			cc_exit(EXIT_USAGE)
+		}
+	} else {
+		preselect(existing)
 	}
 
 	// Enable raw keyboard input mode
-	if err := keyboard.Open(); err != nil {
+	keys, err := openInput()
+	if err != nil {
 		panic(err)
 	}
-	defer keyboard.Close()
+	defer closeInput()
+	resized := watchResize()
 
-	idx := 0
-	renderMenu(existing, idx, true)
+	m := newMenuState(existing, preset)
+	renderMenu(m, true)
+	exitCode := EXIT_NOTHING // Of the last cleanup, used when quitting
 	// Main Input Loop
 	for {
-		char, key, err := keyboard.GetKey()
-		if err != nil {
+		var ev inputEvent
+		var ok bool
+		select {
+		case ev, ok = <-keys:
+		case <-resized:
+			// The layout depends on the terminal size, so everything is drawn again
+			renderMenu(m, true)
+			continue
+		}
+		if !ok || ev.Err != nil {
 			break
 		}
-
-		updated := false
-
-		// Navigation and selection controls
-		if key == keyboard.KeyArrowUp || char == 'w' || char == 'W' {
-			if idx > 0 {
-				idx--
-				updated = true
+		char, key := ev.Rune, ev.Key
+		last := len(m.visible) - 1
+		action := bindings.action(ev)
+
+		updated := true
+
+		// While typing a filter, text keys go into the filter instead of triggering commands
+		if m.filtering {
+			switch {
+			case key == keyboard.KeyEnter:
+				m.filtering = false
+			case key == keyboard.KeyEsc:
+				m.filtering, m.filter = false, ""
+				m.refresh()
+			case key == keyboard.KeyBackspace || key == keyboard.KeyBackspace2:
+				if r := []rune(m.filter); len(r) > 0 {
+					m.filter = string(r[:len(r)-1])
+					m.refresh()
+				}
+			case key == keyboard.KeySpace:
+				m.filter += " "
+				m.refresh()
+			case char != 0:
+				m.filter += string(char)
+				m.refresh()
+			default:
+				updated = false
 			}
-		} else if key == keyboard.KeyArrowDown || char == 's' || char == 'S' {
-			if idx < len(existing)-1 {
-				idx++
-				updated = true
+			if updated {
+				renderMenu(m, false)
+				continue
 			}
-		} else if char == ' ' || key == keyboard.KeyEnter || key == keyboard.KeySpace {
-			existing[idx].Checked = !existing[idx].Checked
 			updated = true
-		} else if char == 'a' || char == 'A' {
-			// Toggle "Select All" logic
-			allChecked := true
-			for _, p := range existing {
-				if !p.Checked {
-					allChecked = false
-					break
+		}
+
+		// A click on an entry moves the cursor there and selects it, the Clean button starts cleaning
+		if c, ok := ev.click(); ok {
+			if i := m.view.top + c.Y - m.view.row - 1; c.Y > m.view.row && i < m.view.end(len(m.visible)) {
+				m.idx, action = i, ACT_TOGGLE
+			} else if c.Y == m.view.button && c.X <= utf8.RuneCountInString(tr("menu.button")) {
+				action = ACT_CLEAN
+			}
+		}
+
+		// Navigation and selection controls, see cc_keys.go for the bindings
+		if idx, ok := moveCursor(ev, m.idx, last+1, m.view.height); ok {
+			m.idx = idx
+		} else if action == ACT_TOGGLE {
+			if p := m.current(); p != nil {
+				p.Checked = !p.Checked
+				m.preset = ""
+			}
+		} else if action == ACT_TOGGLE_ALL {
+			// Toggle "Select All" logic, limited to the entries matching the filter
+			m.toggleAll()
+			m.preset = ""
+		} else if action == ACT_PRESET {
+			// Cycle through the presets, after the last one all entries are unchecked again
+			names := presetNames()
+			next := 0
+			for i, n := range names {
+				if n == m.preset {
+					next = i + 1
 				}
 			}
-			for i := range existing {
-				existing[i].Checked = !allChecked
+			if next < len(names) {
+				m.preset = names[next]
+				applyPreset(m.programs, m.preset)
+			} else {
+				m.preset = ""
+				checkNames(m.programs, nil, true)
 			}
-			updated = true
-		} else if char == 'c' || char == 'C' {
-			runCleanup(existing)
+		} else if action == ACT_BROWSE {
+			if p := m.current(); p != nil {
+				browse(p, keys, resized)
+				m.preset = ""
+				renderMenu(m, true)
+			}
+			updated = false
+		} else if action == ACT_SORT {
+			m.nextSort()
+		} else if action == ACT_FILTER {
+			m.filtering = true
+		} else if action == ACT_HELP {
+			showHelp(keys, resized)
+			renderMenu(m, true)
+			updated = false
+		} else if key == keyboard.KeyEsc && m.filter != "" {
+			m.filter = ""
+			m.refresh()
+		} else if action == ACT_CLEAN {
+			// Without a selection runCleanup reports "Nothing selected" right away
+			if anyChecked(m.programs) && !confirmCleanup(m.programs, keys, resized) {
+				renderMenu(m, true)
+				continue
+			}
+			stop := cancelOnCtrlC(keys)
+			result := runCleanup(m.programs)
+			stop()
+			exitCode = result.ExitCode()
+			if result.Cancelled || !askCleanAgain(keys) {
+				cc_exit(exitCode)
+			}
+
+			// Back to the menu with fresh sizes
+			fmt.Print(CLEAR_SCREEN)
+			fresh := scanWithSpinner()
+			if len(fresh) == 0 {
+				fmt.Printf("\n%s\n", tr("menu.none_left"))
+				cc_exit(exitCode)
+			}
+			m.replace(fresh)
+			renderMenu(m, true)
+			updated = false
 		} else if key == keyboard.KeyCtrlC {
-			cc_exit()
+			cc_exit(EXIT_ABORTED)
+		} else {
+			updated = false
 		}
 
 		// Redraw menu entries in-place if state changed
 		if updated {
-			// Move cursor up to the start of the menu list
-			fmt.Printf("\033[%dA", len(existing))
-			renderMenu(existing, idx, false)
+			renderMenu(m, false)
 		}
 	}
 }
 
-func runCleanup(programs []Program) {
-	beforeFree, _, _ := getDiskMetrics()
-
+func runCleanup(programs []Program) CleanResult {
 	if *Flagdryrun {
-		fmt.Printf("\n%sNOTE: Dry run active. No files will actually be deleted.%s", YELLOW, RC)
+		fmt.Printf("\n%s%s%s", YELLOW, tr("clean.dry_run_note"), RC)
 	} else {
-		fmt.Printf("\nYou use this tool at your own risk!")
+		fmt.Printf("\n%s", tr("clean.risk"))
 	}
-	// Fetching the current system user
-	usr, err := user.Current()
-	if err != nil {
-		fmt.Printf("\nUsername: unknown")
-	} else {
-		name := usr.Username
-		// Strip domain/machine name prefix on Windows
-		if GOOS == "windows" && strings.Contains(name, "\\") {
-			parts := strings.Split(name, "\\")
-			name = parts[len(parts)-1]
+	fmt.Printf("\n%s", tr("clean.user", currentUsername()))
+	// Remember the selection for the next interactive run
+	if !*Flagauto && resumed == nil {
+		if err := saveLastSelection(programs); err != nil {
+			fmt.Printf("\n%s%s%s", YELLOW, tr("clean.selection_error", err), RC)
 		}
-		fmt.Printf("\nUsername: %s", name)
 	}
 	//fmt.Printf("\nPress [CTRL+C] to cancel")
-	fmt.Printf("\nCleaning caches started...\n")
+	fmt.Printf("\n%s\n", tr("clean.started"))
+
+	cancel, done := startCancellable()
+	result := cleanPrograms(programs, CleanOptions{DryRun: *Flagdryrun, Progress: newProgressBar(), Cancel: cancel})
+	done()
+	setProgressLine("")
+	exportMetrics(programs, &result)
+	notifyWebhooks(&result)
+
+	if result.NotNeeded {
+		logOK(tr("clean.not_needed", freeThreshold))
+		return result
+	}
+
+	if len(result.Programs) == 0 && !result.Cancelled {
+		logQuiet(LOG_WARN, tr("clean.nothing"))
+		fmt.Printf("\n%s\n", tr("clean.nothing"))
+		return result
+	}
+
+	switch {
+	case result.Cancelled:
+		logWarn(tr("clean.cancelled", len(result.Pending)))
+	case *Flagdryrun:
+		logOK(tr("clean.sim_finished"))
+	default:
+		logOK(tr("clean.finished"))
+	}
+
+	// Counted from the deleted files, the free space of the disk also changes with everything else running
+	var cleaned int64
+	if !*Flagdryrun {
+		cleaned = result.Bytes()
+	}
+
+	line()
+	fmt.Println(tr("clean.cleaned", YELLOW+formatMB(cleaned)+RC))
+	if errs := result.Errors(); len(errs) > 0 {
+		fmt.Println(tr("clean.errors", fmt.Sprintf("%s%d%s", YELLOW, len(errs), RC)))
+		logQuiet(LOG_WARN, tr("clean.summary_errors", formatMB(result.Bytes()), len(result.Programs), len(errs)))
+	} else {
+		logQuiet(LOG_OK, tr("clean.summary", formatMB(result.Bytes()), len(result.Programs)))
+	}
+
+	for _, pr := range result.Programs {
+		errs := ""
+		if len(pr.Errors) > 0 {
+			errs = " " + YELLOW + tr("clean.row_errors", len(pr.Errors)) + RC
+		}
+		fmt.Printf("  %-30s %s%10s%s %s%s\n", pr.Name, YELLOW, formatMB(pr.Bytes), RC, tr("clean.row_paths", pr.Paths), errs)
+	}
+	if result.Cancelled {
+		saveJournal(&result)
+	}
+	return result
+}
+
+// askCleanAgain waits for the choice after a cleanup: true to go back to the menu, false to quit
+func askCleanAgain(keys <-chan inputEvent) bool {
+	fmt.Printf("\n%s", tr("clean.again"))
+	for ev := range keys {
+		switch {
+		case ev.Err != nil:
+			return false
+		case ev.Rune == 'c' || ev.Rune == 'C':
+			return true
+		case ev.Rune == 'q' || ev.Rune == 'Q' || ev.Key == keyboard.KeyEsc:
+			return false
+		case ev.Key == keyboard.KeyCtrlC:
+			cc_exit(EXIT_ABORTED)
+		}
+	}
+	return false
+}
 
+// scanWithSpinner runs scanForExisting while a spinner shows that something is happening
+func scanWithSpinner() []Program {
 	stop := make(chan bool)
 	ack := make(chan bool)
-	go spinner("Cleaning selected caches", stop, ack)
-	time.Sleep(3 * time.Second)
+	go spinner(tr("scan.spinner"), stop, ack)
+
+	existing := scanForExisting()
+
+	stop <- true // Tell spinner to stop
+	<-ack        // WAIT for spinner to clear the line
+	return existing
+}
 
-	count := 0
+// CleanOptions controls a single cleanPrograms call
+type CleanOptions struct {
+	DryRun   bool
+	Progress func(CleanProgress) // Called before every Program, after every path and while deleting, may be nil
+	Cancel   <-chan struct{}     // Closed to stop after the current path, what is left goes to CleanResult.Pending
+}
 
+// PROGRESS_INTERVAL limits how often CleanOptions.Progress is called while files are deleted
+const PROGRESS_INTERVAL = 100 * time.Millisecond
+
+// CleanProgress reports how far a running cleanup got
+type CleanProgress struct {
+	Program string `json:"program"`
+	Index   int    `json:"index"` // 1-based number of the current Program
+	Total   int    `json:"total"` // Number of selected Programs
+	Path    string `json:"path,omitempty"`
+	Bytes   int64  `json:"bytes"` // Bytes handled so far in this session
+	Files   int    `json:"files"` // Files handled so far in this session
+
+	// Expected totals, from the scan
+	TotalBytes int64 `json:"total_bytes"`
+	TotalFiles int   `json:"total_files"`
+}
+
+// cleanPrograms deletes every checked Program (or only logs it in dry-run mode) and reports what happened.
+// It only logs, the terminal state and exiting are left to the caller.
+func cleanPrograms(programs []Program, opts CleanOptions) CleanResult {
+	result := CleanResult{Start: time.Now(), DryRun: opts.DryRun}
+	if auditLog != nil {
+		auditLog.startSession(result.DryRun)
+	}
+
+	// With -if-free-below only mounts under pressure are cleaned, least valuable Programs first,
+	// until every one of them is above the threshold again
+	var pressure map[string]*Mount
+	if freeThreshold != nil {
+		pressure = pressuredMounts(programs, freeThreshold)
+		if len(pressure) == 0 {
+			result.NotNeeded = true
+			return result
+		}
+		for _, m := range pressure {
+			logInfo(tr("clean.mount_low", m.Path, float64(m.Free)/(1<<30), freeThreshold))
+		}
+		programs = escalate(programs, pressure)
+	}
+
+	progress := CleanProgress{}
 	for _, p := range programs {
+		if p.Checked {
+			progress.Total++
+			progress.TotalBytes += p.Size
+			for _, m := range p.Matches {
+				progress.TotalFiles += m.Files
+			}
+		}
+	}
+	var lastReport time.Time
+	report := func(force bool) {
+		if opts.Progress == nil || !force && time.Since(lastReport) < PROGRESS_INTERVAL {
+			return
+		}
+		lastReport = time.Now()
+		opts.Progress(progress)
+	}
+	removed := func(size int64) {
+		progress.Bytes += size
+		progress.Files++
+		report(false)
+	}
+
+	// freed counts the bytes of path towards its mount, to simulate a dry run against the threshold
+	freed := func(path string, size int64) {
+		if pressure == nil {
+			return
+		}
+		if mnt, err := mountOf(path); err == nil && pressure[mnt] != nil {
+			pressure[mnt].Freed += uint64(size)
+		}
+	}
+
+	cancelled := func() bool {
+		select {
+		case <-opts.Cancel:
+			return true
+		default:
+			return false
+		}
+	}
+
+	for i, p := range programs {
 		if !p.Checked {
 			continue
 		}
-		count++
-
-		for _, path := range p.Paths {
-			matches, _ := filepath.Glob(expandHome(path))
+		if cancelled() {
+			result.Cancelled = true
+			result.Pending = append(result.Pending, checkedPrograms(programs[i:])...)
+			break
+		}
+		if pressure != nil && thresholdMet(pressure, freeThreshold, opts.DryRun) {
+			logInfo(tr("clean.threshold_reached"))
+			break
+		}
 
-			for _, m := range matches {
-				if *Flagdryrun {
-					logInfo("Would clean: " + m)
-					continue
+		name := p.Name
+		pr := ProgramResult{Name: name}
+		if auditLog != nil {
+			auditLog.program = name
+		}
+		progress.Program, progress.Path = name, ""
+		progress.Index++
+		report(true)
+		start := progress.Bytes // Counts every deleted file, so the difference is what p freed
+
+		// The paths found by the scan are deleted, so the cleanup never reaches beyond what was shown
+		var rest []string // Paths left when the cleanup is cancelled within p
+		for k, m := range p.Matches {
+			if isExcluded(m.Path, p.Exclude) {
+				continue
+			}
+			if cancelled() {
+				for _, m := range p.Matches[k:] {
+					if !isExcluded(m.Path, p.Exclude) {
+						rest = append(rest, globEscape(m.Path))
+					}
 				}
-				deletePath(m)
+				break
+			}
+			pr.Paths++
+			progress.Path = m.Path
+			if opts.DryRun {
+				info, errs := statPath(m.Path, p.Exclude)
+				pr.Errors = append(pr.Errors, errs...)
+				freed(m.Path, info.Size)
+				logInfo(tr("clean.would", m.Path))
+				progress.Bytes += info.Size
+				progress.Files += info.Files
+				report(true)
+				continue
 			}
+			// deletePath walks the path anyway, so it is not stat'ed first and every error is reported once
+			before := progress.Bytes
+			pr.Errors = append(pr.Errors, deletePath(m.Path, p.Exclude, removed)...)
+			freed(m.Path, progress.Bytes-before)
+			report(true)
 		}
 
-		// Cut the size part
-		name := p.Name
-		if idx := strings.Index(name, "("); idx != -1 {
-			name = strings.TrimSpace(name[:idx])
+		pr.Bytes = progress.Bytes - start
+
+		if rest != nil {
+			if pr.Paths > 0 {
+				result.Programs = append(result.Programs, pr)
+			}
+			p.Paths, p.Matches, p.Size = rest, nil, 0
+			result.Cancelled = true
+			result.Pending = append(append(result.Pending, p), checkedPrograms(programs[i+1:])...)
+			break
+		}
+
+		result.Programs = append(result.Programs, pr)
+		if len(pr.Errors) > 0 {
+			logWarn(tr("clean.program_errors", name, len(pr.Errors)))
+		} else {
+			logOK(name)
 		}
-		logOK(name)
 	}
 
-	stop <- true
-	<-ack
+	result.Duration = time.Since(result.Start)
+	if err := recordHistory(&result); err != nil {
+		logWarn(tr("clean.history_error", err))
+	}
+	return result
+}
 
-	if count == 0 {
-		fmt.Printf("\nNothing selected")
-		time.Sleep(3 * time.Second)
-		cc_exit()
+// deletePath removes a file, or the contents of a directory (the directory itself is kept),
+// except for the excluded paths. A symlink is removed itself, the folder it points to is left alone.
+// If removed is set, it is called with the size of every deleted file. Every failure is logged and returned.
+func deletePath(path string, exclude []PathInfo, removed func(size int64)) []CleanError {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return []CleanError{{"delete", path, err}}
 	}
 
-	if *Flagdryrun {
-		logOK("Simulation finished")
-	} else {
-		logOK("Cleaning finished")
+	if hasRetention() || auditLog != nil || removed != nil || len(exclude) > 0 {
+		return deleteWalk(path, info, exclude, removed)
 	}
 
-	afterFree, _, _ := getDiskMetrics()
-	cleaned := (afterFree - beforeFree) * 1024
-	if cleaned < 0 || *Flagdryrun {
-		cleaned = 0
+	if !info.IsDir() {
+		if err := os.Remove(path); err != nil {
+			logWarn(tr("delete.skipped", path, err))
+			return []CleanError{{"delete", path, err}}
+		}
+		return nil
 	}
 
-	line()
-	fmt.Printf("CrunchyCleaner cleaned: %s%.2f MB%s\n", YELLOW, cleaned, RC)
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		logWarn(tr("delete.cannot_read", path, err))
+		return []CleanError{{"delete", path, err}}
+	}
 
-	if !*Flagauto {
-		keyboard.Close()
-		fmt.Printf("\nPress [ENTER] to exit")
-		bufio.NewReader(os.Stdin).ReadBytes('\n')
+	var errs []CleanError
+	for _, e := range entries {
+		full := filepath.Join(path, e.Name())
+		if err := os.RemoveAll(full); err != nil {
+			logWarn(tr("delete.skipped", e.Name(), err))
+			errs = append(errs, CleanError{"delete", full, err})
+		}
 	}
-	cc_exit()
+	return errs
 }
 
-func deletePath(path string) {
-	info, err := os.Stat(path)
-	if err != nil {
-		return
+// deleteWalk deletes file by file, so the retention rules and exclusions apply and every path can be audited.
+// Directories emptied this way are removed afterwards, the top-level path is kept.
+func deleteWalk(path string, info os.FileInfo, exclude []PathInfo, removed func(size int64)) []CleanError {
+	var errs []CleanError
+	removeFile := func(p string, fi os.FileInfo) {
+		if reason := retainReason(p, fi); reason != "" {
+			audit("skipped", p, fi, reason)
+			return
+		}
+		if err := os.Remove(p); err != nil {
+			logWarn(tr("delete.skipped", p, err))
+			errs = append(errs, CleanError{"delete", p, err})
+			audit("skipped", p, fi, "error: "+err.Error())
+			return
+		}
+		audit("removed", p, fi, "cache cleanup")
+		if removed != nil {
+			removed(fi.Size())
+		}
 	}
 
 	if !info.IsDir() {
-		_ = os.Remove(path)
-		return
+		removeFile(path, info)
+		return errs
 	}
 
-	entries, err := os.ReadDir(path)
-	if err != nil {
-		logWarn("Cannot read " + path + ": " + err.Error())
-		return
+	type dirEntry struct {
+		path string
+		info os.FileInfo
+	}
+	var dirs []dirEntry
+	filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			logWarn(tr("delete.cannot_read", p, err))
+			errs = append(errs, CleanError{"delete", p, err})
+			audit("skipped", p, fi, "error: "+err.Error())
+			return nil
+		}
+		if isExcluded(p, exclude) {
+			audit("skipped", p, fi, "excluded in the browser")
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if fi.IsDir() {
+			if p != path {
+				dirs = append(dirs, dirEntry{p, fi})
+			}
+			return nil
+		}
+		removeFile(p, fi)
+		return nil
+	})
+
+	// Deepest directories first; non-empty ones still hold retained files and are kept
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if os.Remove(dirs[i].path) == nil {
+			audit("removed", dirs[i].path, dirs[i].info, "empty after cleanup")
+		}
 	}
+	return errs
+}
 
-	for _, e := range entries {
-		full := filepath.Join(path, e.Name())
-		if err := os.RemoveAll(full); err != nil {
-			logWarn("Skipped " + e.Name() + ": " + err.Error())
+// isExcluded reports whether path is one of the excluded paths or inside one of them
+func isExcluded(path string, exclude []PathInfo) bool {
+	for _, e := range exclude {
+		if path == e.Path || strings.HasPrefix(path, e.Path+string(filepath.Separator)) {
+			return true
 		}
 	}
+	return false
+}
+
+// audit records a path in the audit log, if one is enabled
+func audit(action, path string, info os.FileInfo, reason string) {
+	if auditLog != nil {
+		auditLog.record(action, path, info, reason)
+	}
 }
 
 func main() {
 	flag.Parse()
 
-	// Capture OS Interrupts (like Ctrl+C) for graceful shutdown
+	// Load the config file, flags always win over config values
+	path := *Flagconfig
+	if path == "" {
+		path = filepath.Join(configDir(), "config.toml")
+	}
+	conf, err := loadConfig(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Config error: %v\n", err)
+		os.Exit(EXIT_USAGE)
+	}
+	cfg = conf
+	applyConfig(cfg)
+
+	if err := setupTheme(cmp.Or(*Flagcolor, cfg.Color), cmp.Or(*Flagtheme, cfg.Theme, "default"), cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Theme error: %v\n", err)
+		os.Exit(EXIT_USAGE)
+	}
+	if err := setupLanguage(cmp.Or(*Flaglang, cfg.Language)); err != nil {
+		fmt.Fprintf(os.Stderr, "Language error: %v\n", err)
+		os.Exit(EXIT_USAGE)
+	}
+	if err := setupKeymap(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Config error: %s: [keys] %v\n", cfg.Path, err)
+		os.Exit(EXIT_USAGE)
+	}
+
+	if err := setupLogging(cfg, *Flaglogfile, *Flagsyslog); err != nil {
+		fmt.Fprintf(os.Stderr, "Logging error: %v\n", err)
+		os.Exit(EXIT_USAGE)
+	}
+
+	// Capture OS Interrupts (like Ctrl+C) for graceful shutdown.
+	// A running cleanup is asked to stop after the current path, a second interrupt quits at once.
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	go func() {
-		<-c
-		cc_exit()
+		for sig := range c {
+			if requestCancel() {
+				continue
+			}
+			logQuiet(LOG_WARN, tr("app.aborted", sig))
+			cc_exit(EXIT_ABORTED)
+		}
 	}()
 
 	if *Flagversion {
@@ -701,6 +1360,67 @@ func main() {
 		return
 	}
 
+	if path := *Flagaudit; path != "" || cfg.AuditLog != "" {
+		if path == "" {
+			path = expandHome(cfg.AuditLog)
+		}
+		a, err := openAuditLog(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot open audit log: %v\n", err)
+			os.Exit(EXIT_USAGE)
+		}
+		auditLog = a
+	}
+
+	metricsFile = *Flagmetrics
+	if metricsFile == "" {
+		metricsFile = expandHome(cfg.MetricsTextfile)
+	}
+
+	// Subcommands
+	if args := flag.Args(); len(args) > 0 {
+		switch args[0] {
+		case "preset":
+			os.Exit(presetCommand(args[1:]))
+		case "install-schedule":
+			os.Exit(installScheduleCommand(args[1:]))
+		case "uninstall-schedule":
+			os.Exit(uninstallScheduleCommand(args[1:]))
+		case "daemon":
+			os.Exit(daemonCommand(args[1:]))
+		case "history":
+			os.Exit(historyCommand(args[1:]))
+		case "serve":
+			os.Exit(serveCommand(args[1:]))
+		case "explore":
+			cc_exit(exploreCommand(args[1:]))
+		case "resume":
+			cc_exit(resumeCommand(args[1:]))
+		case "lang":
+			os.Exit(langCommand(args[1:]))
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown command %q\n", args[0])
+			flag.Usage()
+			os.Exit(EXIT_USAGE)
+		}
+	}
+
+	if *Flagiffree != "" {
+		t, err := parseFreeThreshold(*Flagiffree)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "-if-free-below: %v\n", err)
+			os.Exit(EXIT_USAGE)
+		}
+		freeThreshold = t
+	}
+
+	if *Flagpreset != "" {
+		if _, ok := presets()[*Flagpreset]; !ok {
+			fmt.Fprintf(os.Stderr, "Unknown preset %q\n", *Flagpreset)
+			os.Exit(EXIT_USAGE)
+		}
+	}
+
 	if !*Flagnoinit && !*Flagauto {
 		initApp()
 	}
@@ -708,16 +1428,21 @@ func main() {
 	// AUTOMATION LOGIC
 	if *Flagauto {
 		showBanner()
-		fmt.Printf("%sNOTE: Automation active. Scanning and selecting all caches...%s\n", YELLOW, RC)
 		existing := scanForExisting()
 
-		// Check all found items
-		for i := range existing {
-			existing[i].Checked = true
+		if *Flagpreset != "" {
+			fmt.Printf("%s%s%s\n", YELLOW, tr("auto.preset", *Flagpreset), RC)
+		} else {
+			fmt.Printf("%s%s%s\n", YELLOW, tr("auto.all"), RC)
 		}
-		runCleanup(existing)
+		selectForAuto(existing, *Flagpreset)
+		result := runCleanup(existing)
+		cc_exit(result.ExitCode())
 	}
 
 	// Run interactive mode
-	handleMenu()
+	if *Flagaccessible {
+		accessibleMenu(*Flagpreset)
+	}
+	handleMenu(*Flagpreset)
 }
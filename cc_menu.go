@@ -0,0 +1,195 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SORT_MODES are cycled with the sort key, "catalog" keeps the order of getPrograms
+var SORT_MODES = []string{"catalog", "size", "name", "category", "age"}
+
+// menuState is what the interactive menu shows, independent of the terminal layout
+type menuState struct {
+	programs  []Program // All scanned Programs in catalog order, selection state lives here
+	visible   []int     // Indexes into programs, sorted and filtered
+	idx       int       // Cursor position within visible
+	preset    string
+	sort      string
+	filter    string
+	filtering bool // Keys are typed into the filter after '/'
+	view      viewport
+}
+
+func newMenuState(programs []Program, preset string) *menuState {
+	m := &menuState{programs: programs, preset: preset, sort: SORT_MODES[0]}
+	m.refresh()
+	return m
+}
+
+// current returns the Program under the cursor, or nil if the filter hides everything
+func (m *menuState) current() *Program {
+	if m.idx >= len(m.visible) {
+		return nil
+	}
+	return &m.programs[m.visible[m.idx]]
+}
+
+// refresh sorts and filters the list again, keeping the cursor on the same Program if it is still visible
+func (m *menuState) refresh() {
+	cur := -1
+	if m.idx < len(m.visible) {
+		cur = m.visible[m.idx]
+	}
+
+	query := strings.ToLower(m.filter)
+	m.visible = m.visible[:0]
+	for i, p := range m.programs {
+		if query == "" || strings.Contains(strings.ToLower(p.Name+"\x00"+p.Category), query) {
+			m.visible = append(m.visible, i)
+		}
+	}
+	sort.SliceStable(m.visible, func(a, b int) bool {
+		return lessPrograms(&m.programs[m.visible[a]], &m.programs[m.visible[b]], m.sort)
+	})
+
+	m.idx = 0
+	for i, v := range m.visible {
+		if v == cur {
+			m.idx = i
+		}
+	}
+}
+
+// lessPrograms orders two Programs by the given sort mode, "catalog" leaves them as they are
+func lessPrograms(a, b *Program, mode string) bool {
+	switch mode {
+	case "size":
+		return a.Size > b.Size
+	case "name":
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	case "category":
+		return a.Category < b.Category
+	case "age":
+		// Caches nobody wrote to for the longest time first, empty ones last
+		an, bn := a.newest(), b.newest()
+		if an.IsZero() || bn.IsZero() {
+			return !an.IsZero() && bn.IsZero()
+		}
+		return an.Before(bn)
+	}
+	return false
+}
+
+// newest returns the modification time of the newest file found by the scan
+func (p *Program) newest() time.Time {
+	var t time.Time
+	for _, m := range p.Matches {
+		if m.Newest.After(t) {
+			t = m.Newest
+		}
+	}
+	return t
+}
+
+// nextSort switches to the next sort mode
+func (m *menuState) nextSort() {
+	for i, s := range SORT_MODES {
+		if s == m.sort {
+			m.sort = SORT_MODES[(i+1)%len(SORT_MODES)]
+			break
+		}
+	}
+	m.refresh()
+}
+
+// toggleAll checks every visible Program, or unchecks them if all of them are checked already
+func (m *menuState) toggleAll() {
+	allChecked := true
+	for _, i := range m.visible {
+		if !m.programs[i].Checked {
+			allChecked = false
+			break
+		}
+	}
+	for _, i := range m.visible {
+		m.programs[i].Checked = !allChecked
+	}
+}
+
+// anyChecked reports whether at least one Program is checked
+func anyChecked(programs []Program) bool {
+	for _, p := range programs {
+		if p.Checked {
+			return true
+		}
+	}
+	return false
+}
+
+// replace swaps in the Programs of a new scan, keeping selection, exclusions and cursor by name.
+// Exclusions that no longer exist or lie outside the new matches are dropped.
+func (m *menuState) replace(programs []Program) {
+	var checked []string
+	exclude := map[string][]PathInfo{}
+	for _, p := range m.programs {
+		if p.Checked {
+			checked = append(checked, p.Name)
+			exclude[p.Name] = p.Exclude
+		}
+	}
+	cur := ""
+	if p := m.current(); p != nil {
+		cur = p.Name
+	}
+
+	checkNames(programs, checked, true)
+	for i := range programs {
+		p := &programs[i]
+		for _, e := range exclude[p.Name] {
+			if _, err := os.Lstat(e.Path); err == nil && isExcluded(e.Path, p.Matches) {
+				info, _ := statPath(e.Path, nil)
+				p.Exclude = append(p.Exclude, info)
+			}
+		}
+		if len(p.Exclude) > 0 {
+			p.resize("")
+		}
+	}
+	m.programs, m.visible, m.idx = programs, nil, 0
+	m.refresh()
+	for i, v := range m.visible {
+		if m.programs[v].Name == cur {
+			m.idx = i
+		}
+	}
+}
+
+// files returns the number of files the cleanup of p would delete
+func (p *Program) files() int {
+	n := 0
+	for _, m := range p.Matches {
+		n += m.Files
+	}
+	return n
+}
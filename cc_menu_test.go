@@ -0,0 +1,86 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// excludeTree creates root/keep (100 bytes plus a retained 50 byte file) and root/gone (200 bytes)
+func excludeTree(t *testing.T) string {
+	root := t.TempDir()
+	for path, size := range map[string]int{"keep/a": 100, "keep/b.keep": 50, "gone/c": 200} {
+		path = filepath.Join(root, path)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	saved := cfg
+	cfg = &Config{Keep: []string{"*.keep"}}
+	t.Cleanup(func() { cfg = saved })
+	return root
+}
+
+func TestReplaceKeepsExclusions(t *testing.T) {
+	root := excludeTree(t)
+	scan := func() []Program {
+		p := Program{Name: "Cache", Paths: []string{root}}
+		p.scan()
+		return []Program{p}
+	}
+
+	m := &menuState{programs: scan()}
+	b := &browser{p: &m.programs[0]}
+	b.include(root)
+	b.exclude(filepath.Join(root, "keep"))
+	m.programs[0].Exclude = append(m.programs[0].Exclude, PathInfo{Path: filepath.Join(root, "deleted")})
+
+	m.replace(scan())
+	p := m.programs[0]
+	if !p.Checked || len(p.Exclude) != 1 || p.Exclude[0].Path != filepath.Join(root, "keep") {
+		t.Fatalf("after rescan: checked %v, exclude %+v", p.Checked, p.Exclude)
+	}
+	if p.Size != 200 || p.files() != 1 {
+		t.Errorf("after rescan: size %d in %d files, want 200 in 1", p.Size, p.files())
+	}
+
+	// Excluding everything unchecks the Program and sizes it in full again
+	b = &browser{p: &m.programs[0]}
+	b.exclude(root)
+	if p := m.programs[0]; p.Checked || p.Exclude != nil || p.Size != 300 {
+		t.Errorf("fully excluded: checked %v, exclude %+v, size %d", p.Checked, p.Exclude, p.Size)
+	}
+}
+
+func TestSummarizeExcluded(t *testing.T) {
+	root := excludeTree(t)
+	// As restored from a journal: matches sized with the exclusion applied
+	p := Program{Name: "Cache", Paths: []string{root}, Checked: true}
+	p.Exclude = []PathInfo{{Path: filepath.Join(root, "keep"), Size: 100, Files: 1}}
+	p.scan()
+
+	s := summarize([]Program{p})
+	if s.bytes != 200 || s.files != 1 || len(s.mounts) != 1 || s.mounts[0].Freed != 200 {
+		t.Errorf("summary: %d bytes in %d files, mounts %+v; want 200 bytes in 1 file", s.bytes, s.files, s.mounts)
+	}
+}
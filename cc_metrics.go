@@ -0,0 +1,107 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// metricsFile is set from -metrics-textfile or [metrics] textfile, empty disables metrics
+var metricsFile string
+
+// promLabel escapes a Prometheus label value
+func promLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// writeMetrics writes node_exporter textfile gauges for the last scan and cleanup.
+// The file is written to a temporary name first and renamed, so the exporter never reads half a file.
+func writeMetrics(path string, existing []Program, r *CleanResult) error {
+	var b strings.Builder
+	gauge := func(name, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	}
+
+	gauge("crunchycleaner_detected_bytes", "Cache size found by the last scan.")
+	for _, p := range existing {
+		fmt.Fprintf(&b, "crunchycleaner_detected_bytes{program=\"%s\"} %d\n", promLabel(p.Name), p.Size)
+	}
+
+	gauge("crunchycleaner_reclaimed_bytes", "Bytes deleted (or found, in a dry run) by the last cleanup.")
+	for _, p := range r.Programs {
+		fmt.Fprintf(&b, "crunchycleaner_reclaimed_bytes{program=\"%s\"} %d\n", promLabel(p.Name), p.Bytes)
+	}
+
+	gauge("crunchycleaner_errors", "Errors of the last cleanup.")
+	for _, p := range r.Programs {
+		fmt.Fprintf(&b, "crunchycleaner_errors{program=\"%s\"} %d\n", promLabel(p.Name), len(p.Errors))
+	}
+
+	gauge("crunchycleaner_run_errors", "Errors of the last cleanup across all caches.")
+	fmt.Fprintf(&b, "crunchycleaner_run_errors %d\n", len(r.Errors()))
+
+	gauge("crunchycleaner_last_run_timestamp_seconds", "Unix time the last cleanup started.")
+	fmt.Fprintf(&b, "crunchycleaner_last_run_timestamp_seconds %d\n", r.Start.Unix())
+
+	gauge("crunchycleaner_run_duration_seconds", "Duration of the last cleanup.")
+	fmt.Fprintf(&b, "crunchycleaner_run_duration_seconds %g\n", r.Duration.Seconds())
+
+	dry := 0
+	if r.DryRun {
+		dry = 1
+	}
+	gauge("crunchycleaner_dry_run", "1 if the last cleanup was a dry run.")
+	fmt.Fprintf(&b, "crunchycleaner_dry_run %d\n", dry)
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".crunchycleaner-metrics-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+	if _, err := tmp.WriteString(b.String()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// exportMetrics writes the metrics file if one is configured and logs failures
+func exportMetrics(existing []Program, r *CleanResult) {
+	if metricsFile == "" {
+		return
+	}
+	if err := writeMetrics(metricsFile, existing, r); err != nil {
+		logWarn(tr("metrics.error", err))
+	}
+}
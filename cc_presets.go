@@ -0,0 +1,172 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// builtinPresets are always available, a [presets.<name>] table in the config overrides them.
+// Names of both platforms are listed, entries missing on the current OS are simply ignored.
+var builtinPresets = map[string][]string{
+	"dev": {"Go Build Cache", "Pip Cache", "NPM Cache", "Yarn Cache", "Cargo Cache", "VS Code Cache"},
+	"browsers": {
+		"Firefox Cache", "Chrome Cache", "Chromium Cache", "Edge Cache", "Brave Cache", "Opera Cache",
+	},
+	"gamer": {
+		"Steam Cache", "Epic Games Cache", "Epic Games (Heroic/Lutris) Cache", "Shader Cache", "Discord Cache",
+	},
+}
+
+// presets returns the built-in presets merged with the ones from the config
+func presets() map[string][]string {
+	all := map[string][]string{}
+	for name, progs := range builtinPresets {
+		all[name] = progs
+	}
+	for name, progs := range cfg.Presets {
+		all[name] = progs
+	}
+	return all
+}
+
+// presetNames returns all preset names in alphabetical order
+func presetNames() []string {
+	var names []string
+	for name := range presets() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyPreset checks exactly the Programs listed in the named preset
+func applyPreset(programs []Program, name string) error {
+	list, ok := presets()[name]
+	if !ok {
+		return fmt.Errorf("unknown preset %q (see 'crunchycleaner preset list')", name)
+	}
+	checkNames(programs, list, true)
+	return nil
+}
+
+// checkNames checks every Program whose name is in list (case-insensitive).
+// With exclusive set, all other Programs are unchecked.
+func checkNames(programs []Program, list []string, exclusive bool) {
+	names := map[string]bool{}
+	for _, n := range list {
+		names[strings.ToLower(n)] = true
+	}
+	for i := range programs {
+		if names[strings.ToLower(programs[i].Name)] {
+			programs[i].Checked = true
+		} else if exclusive {
+			programs[i].Checked = false
+		}
+	}
+}
+
+// ========================= PRESET COMMAND =========================
+
+// presetCommand implements 'crunchycleaner preset list|show|create|delete'
+func presetCommand(args []string) int {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+	switch args[0] {
+	case "list":
+		for _, name := range presetNames() {
+			source := tr("preset.builtin")
+			if _, ok := cfg.Presets[name]; ok {
+				source = tr("preset.config")
+			}
+			fmt.Printf("%s%-12s%s %-9s %s\n", YELLOW, name, RC, source, strings.Join(presets()[name], ", "))
+		}
+		return EXIT_OK
+
+	case "show":
+		if len(args) != 2 {
+			break
+		}
+		list, ok := presets()[args[1]]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Unknown preset %q\n", args[1])
+			return EXIT_USAGE
+		}
+		for _, n := range list {
+			fmt.Println(n)
+		}
+		return EXIT_OK
+
+	case "create":
+		if len(args) < 3 {
+			break
+		}
+		known := map[string]bool{}
+		for _, p := range getPrograms() {
+			known[strings.ToLower(p.Name)] = true
+		}
+		for _, n := range args[2:] {
+			if !known[strings.ToLower(n)] {
+				logWarn(tr("preset.not_in_catalog", n))
+			}
+		}
+		if err := writePreset(args[1], args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not save preset: %v\n", err)
+			return EXIT_PARTIAL
+		}
+		logOK(tr("preset.saved", args[1], cfg.Path))
+		return EXIT_OK
+
+	case "delete":
+		if len(args) != 2 {
+			break
+		}
+		if _, ok := cfg.Presets[args[1]]; !ok {
+			fmt.Fprintf(os.Stderr, "Preset %q is not defined in %s\n", args[1], cfg.Path)
+			return EXIT_USAGE
+		}
+		if err := writePreset(args[1], nil); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not delete preset: %v\n", err)
+			return EXIT_PARTIAL
+		}
+		logOK(tr("preset.deleted", args[1]))
+		return EXIT_OK
+	}
+
+	fmt.Fprintf(os.Stderr, "Usage:\n"+
+		"  crunchycleaner preset list\n"+
+		"  crunchycleaner preset show <name>\n"+
+		"  crunchycleaner preset create <name> <program> [program...]\n"+
+		"  crunchycleaner preset delete <name>\n")
+	return EXIT_USAGE
+}
+
+// writePreset replaces (or with programs == nil removes) the [presets.<name>] table in the config file
+func writePreset(name string, programs []string) error {
+	body := ""
+	if programs != nil {
+		body = "programs = " + tomlStrings(programs) + "\n"
+	}
+	return writeConfigTable([]string{"presets", name}, body)
+}
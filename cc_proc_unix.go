@@ -0,0 +1,45 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// isPrivileged reports whether the process runs as root
+func isPrivileged() bool {
+	return os.Geteuid() == 0
+}
+
+// runningProcesses returns the lowercase names of all running processes, read from /proc
+func runningProcesses() map[string]bool {
+	names := map[string]bool{}
+	comms, _ := filepath.Glob("/proc/[0-9]*/comm")
+	for _, c := range comms {
+		if data, err := os.ReadFile(c); err == nil {
+			names[strings.ToLower(strings.TrimSpace(string(data)))] = true
+		}
+	}
+	return names
+}
@@ -0,0 +1,471 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	SCHEDULE_NAME   = "crunchycleaner"
+	SCHEDULE_MARKER = "# crunchycleaner-schedule" // Tags our crontab line
+)
+
+// scheduleOptions are the flags shared by install-schedule and daemon
+type scheduleOptions struct {
+	Interval string
+	Preset   string
+	IfFree   string
+	DryRun   bool
+}
+
+// parseScheduleFlags parses the flags of a schedule subcommand, defaulting to the global flags
+func parseScheduleFlags(name string, args []string) (*scheduleOptions, error) {
+	opts := &scheduleOptions{}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&opts.Interval, "interval", "daily", "hourly, daily, weekly, monthly or a duration like 6h")
+	fs.StringVar(&opts.Preset, "preset", *Flagpreset, "Clean this preset instead of everything")
+	fs.StringVar(&opts.IfFree, "if-free-below", *Flagiffree, "Only clean if free space is below this (e.g. 10GB or 15%)")
+	fs.BoolVar(&opts.DryRun, "d", *Flagdryrun, "Simulation mode without deleting files")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if opts.Preset != "" {
+		if _, ok := presets()[opts.Preset]; !ok {
+			return nil, fmt.Errorf("unknown preset %q", opts.Preset)
+		}
+	}
+	if opts.IfFree != "" {
+		if _, err := parseFreeThreshold(opts.IfFree); err != nil {
+			return nil, err
+		}
+	}
+	return opts, nil
+}
+
+// Interval is either a calendar name (daily, weekly, ...) or a fixed duration
+type Interval struct {
+	Calendar string
+	Every    time.Duration
+}
+
+var calendarIntervals = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+}
+
+func parseInterval(s string) (Interval, error) {
+	if d, ok := calendarIntervals[s]; ok {
+		return Interval{Calendar: s, Every: d}, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return Interval{}, fmt.Errorf("invalid interval %q (use hourly, daily, weekly, monthly or e.g. 6h)", s)
+	}
+	if d < time.Minute {
+		return Interval{}, fmt.Errorf("interval %s is shorter than one minute", d)
+	}
+	return Interval{Every: d}, nil
+}
+
+// cron converts the interval to a crontab time expression
+func (iv Interval) cron() (string, error) {
+	if iv.Calendar != "" {
+		return "@" + iv.Calendar, nil
+	}
+	if iv.Every%time.Hour == 0 && 24%int(iv.Every/time.Hour) == 0 {
+		return fmt.Sprintf("0 */%d * * *", int(iv.Every/time.Hour)), nil
+	}
+	if iv.Every < time.Hour && iv.Every%time.Minute == 0 && 60%int(iv.Every/time.Minute) == 0 {
+		return fmt.Sprintf("*/%d * * * *", int(iv.Every/time.Minute)), nil
+	}
+	return "", fmt.Errorf("interval %s cannot be expressed in cron", iv.Every)
+}
+
+// schtasks converts the interval to the /SC and /MO arguments of schtasks,
+// which takes at most 1439 minutes, 23 hours or 365 days
+func (iv Interval) schtasks() ([]string, error) {
+	const day = 24 * time.Hour
+	switch {
+	case iv.Calendar != "":
+		return []string{"/SC", strings.ToUpper(iv.Calendar)}, nil
+	case iv.Every%day == 0 && iv.Every <= 365*day:
+		return []string{"/SC", "DAILY", "/MO", fmt.Sprint(int(iv.Every / day))}, nil
+	case iv.Every%time.Hour == 0 && iv.Every < day:
+		return []string{"/SC", "HOURLY", "/MO", fmt.Sprint(int(iv.Every / time.Hour))}, nil
+	case iv.Every%time.Minute == 0 && iv.Every < day:
+		return []string{"/SC", "MINUTE", "/MO", fmt.Sprint(int(iv.Every / time.Minute))}, nil
+	}
+	return nil, fmt.Errorf("interval %s cannot be expressed in the Task Scheduler", iv.Every)
+}
+
+// scheduledArgs returns the command line a scheduled run uses
+func scheduledArgs(opts *scheduleOptions) ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	args := []string{exe, "-a", "-t"}
+	if opts.DryRun {
+		args = append(args, "-d")
+	}
+	if opts.Preset != "" {
+		args = append(args, "-preset", opts.Preset)
+	}
+	if opts.IfFree != "" {
+		args = append(args, "-if-free-below", opts.IfFree)
+	}
+	if *Flagconfig != "" {
+		conf, err := filepath.Abs(*Flagconfig)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "-config", conf)
+	}
+	return args, nil
+}
+
+// systemdArgs formats args for ExecStart=, double-quoting where needed.
+// '%' is a specifier in systemd units and has to be doubled.
+func systemdArgs(args []string) string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\"'\\") {
+			a = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(a) + `"`
+		}
+		out[i] = strings.ReplaceAll(a, "%", "%%")
+	}
+	return strings.Join(out, " ")
+}
+
+// windowsArgs formats args for schtasks /TR, Windows paths keep their backslashes
+func windowsArgs(args []string) string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t") {
+			a = `"` + a + `"`
+		}
+		out[i] = a
+	}
+	return strings.Join(out, " ")
+}
+
+// cronArgs formats args for a crontab line: single-quoted for sh, and '%' escaped
+// because cron turns it into a newline
+func cronArgs(args []string) string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\"'\\$`;&|<>()*?") {
+			a = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+		}
+		out[i] = strings.ReplaceAll(a, "%", `\%`)
+	}
+	return strings.Join(out, " ")
+}
+
+// ========================= INSTALL / UNINSTALL =========================
+
+// installScheduleCommand implements 'crunchycleaner install-schedule'
+func installScheduleCommand(args []string) int {
+	opts, err := parseScheduleFlags("install-schedule", args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "install-schedule: %v\n", err)
+		return EXIT_USAGE
+	}
+	iv, err := parseInterval(opts.Interval)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "install-schedule: %v\n", err)
+		return EXIT_USAGE
+	}
+	cmd, err := scheduledArgs(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "install-schedule: %v\n", err)
+		return EXIT_PARTIAL
+	}
+
+	if GOOS == "windows" {
+		err = installTaskScheduler(cmd, iv)
+	} else if err = installSystemd(cmd, iv); err != nil {
+		logWarn(tr("schedule.cron_fallback", err))
+		err = installCron(cmd, iv)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "install-schedule: %v\n", err)
+		return EXIT_PARTIAL
+	}
+	logOK(tr("schedule.installed", strings.Join(cmd[1:], " "), opts.Interval))
+	return EXIT_OK
+}
+
+// uninstallScheduleCommand implements 'crunchycleaner uninstall-schedule'
+func uninstallScheduleCommand(args []string) int {
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "uninstall-schedule: unexpected argument %q\n", args[0])
+		return EXIT_USAGE
+	}
+	if GOOS == "windows" {
+		if out, err := exec.Command("schtasks", "/Delete", "/TN", "CrunchyCleaner", "/F").CombinedOutput(); err != nil {
+			fmt.Fprintf(os.Stderr, "uninstall-schedule: %v: %s\n", err, strings.TrimSpace(string(out)))
+			return EXIT_PARTIAL
+		}
+		logOK(tr("schedule.task_removed"))
+		return EXIT_OK
+	}
+
+	removed := false
+	if uninstallSystemd() {
+		logOK(tr("schedule.timer_removed"))
+		removed = true
+	}
+	if ok, err := uninstallCron(); err != nil {
+		logWarn(tr("schedule.cron_error", err))
+	} else if ok {
+		logOK(tr("schedule.cron_removed"))
+		removed = true
+	}
+	if !removed {
+		logInfo(tr("schedule.none"))
+	}
+	return EXIT_OK
+}
+
+func systemdUserDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "systemd", "user")
+}
+
+// installSystemd writes and enables crunchycleaner.service + crunchycleaner.timer as user units
+func installSystemd(cmd []string, iv Interval) error {
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		return err
+	}
+	if err := exec.Command("systemctl", "--user", "show-environment").Run(); err != nil {
+		return errors.New("no user session manager running")
+	}
+
+	service := fmt.Sprintf(`[Unit]
+Description=CrunchyCleaner cache cleanup
+
+[Service]
+Type=oneshot
+ExecStart=%s
+`, systemdArgs(cmd))
+
+	trigger := "OnCalendar=" + iv.Calendar
+	if iv.Calendar == "" {
+		trigger = fmt.Sprintf("OnBootSec=5min\nOnUnitActiveSec=%s", iv.Every)
+	}
+	timer := fmt.Sprintf(`[Unit]
+Description=%s
+
+[Timer]
+%s
+Persistent=true
+
+[Install]
+WantedBy=timers.target
+`, strings.TrimSpace("Run CrunchyCleaner "+iv.Calendar), trigger)
+
+	dir := systemdUserDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	servicePath := filepath.Join(dir, SCHEDULE_NAME+".service")
+	timerPath := filepath.Join(dir, SCHEDULE_NAME+".timer")
+	if err := os.WriteFile(servicePath, []byte(service), 0o644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(timerPath, []byte(timer), 0o644); err != nil {
+		os.Remove(servicePath)
+		return err
+	}
+
+	for _, c := range [][]string{{"daemon-reload"}, {"enable", "--now", SCHEDULE_NAME + ".timer"}} {
+		if out, err := exec.Command("systemctl", append([]string{"--user"}, c...)...).CombinedOutput(); err != nil {
+			os.Remove(servicePath)
+			os.Remove(timerPath)
+			return fmt.Errorf("systemctl %s: %s", c[0], strings.TrimSpace(string(out)))
+		}
+	}
+	logInfo(tr("schedule.wrote", servicePath))
+	logInfo(tr("schedule.wrote", timerPath))
+	return nil
+}
+
+// uninstallSystemd disables and removes the user units, reporting whether any existed
+func uninstallSystemd() bool {
+	dir := systemdUserDir()
+	timerPath := filepath.Join(dir, SCHEDULE_NAME+".timer")
+	servicePath := filepath.Join(dir, SCHEDULE_NAME+".service")
+	if _, err := os.Stat(timerPath); err != nil {
+		return false
+	}
+	exec.Command("systemctl", "--user", "disable", "--now", SCHEDULE_NAME+".timer").Run()
+	os.Remove(timerPath)
+	os.Remove(servicePath)
+	exec.Command("systemctl", "--user", "daemon-reload").Run()
+	return true
+}
+
+// readCrontab returns the current user crontab without our own entry
+func readCrontab() ([]string, bool, error) {
+	if _, err := exec.LookPath("crontab"); err != nil {
+		return nil, false, err
+	}
+	// 'crontab -l' fails when there is no crontab yet, which is fine
+	out, _ := exec.Command("crontab", "-l").Output()
+	var lines []string
+	found := false
+	for _, l := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if strings.HasSuffix(l, SCHEDULE_MARKER) {
+			found = true
+			continue
+		}
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines, found, nil
+}
+
+func writeCrontab(lines []string) error {
+	c := exec.Command("crontab", "-")
+	c.Stdin = strings.NewReader(strings.Join(lines, "\n") + "\n")
+	if out, err := c.CombinedOutput(); err != nil {
+		return fmt.Errorf("crontab: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// installCron adds (or replaces) our line in the user crontab, logging to the state directory
+func installCron(cmd []string, iv Interval) error {
+	expr, err := iv.cron()
+	if err != nil {
+		return err
+	}
+	lines, _, err := readCrontab()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(stateDir(), 0o755); err != nil {
+		return err
+	}
+	logFile := filepath.Join(stateDir(), "schedule.log")
+	lines = append(lines, fmt.Sprintf("%s %s >> %s 2>&1 %s", expr, cronArgs(cmd), cronArgs([]string{logFile}), SCHEDULE_MARKER))
+	if err := writeCrontab(lines); err != nil {
+		return err
+	}
+	logInfo(tr("schedule.cron_added", logFile))
+	return nil
+}
+
+// uninstallCron removes our line from the user crontab, reporting whether it existed
+func uninstallCron() (bool, error) {
+	lines, found, err := readCrontab()
+	if err != nil || !found {
+		return false, nil
+	}
+	return true, writeCrontab(lines)
+}
+
+// installTaskScheduler registers a Windows scheduled task
+func installTaskScheduler(cmd []string, iv Interval) error {
+	schedule, err := iv.schtasks()
+	if err != nil {
+		return err
+	}
+	args := append([]string{"/Create", "/F", "/TN", "CrunchyCleaner", "/TR", windowsArgs(cmd)}, schedule...)
+	if out, err := exec.Command("schtasks", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ========================= DAEMON =========================
+
+// daemonCommand implements 'crunchycleaner daemon': clean in the foreground every interval
+func daemonCommand(args []string) int {
+	opts, err := parseScheduleFlags("daemon", args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "daemon: %v\n", err)
+		return EXIT_USAGE
+	}
+	iv, err := parseInterval(opts.Interval)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "daemon: %v\n", err)
+		return EXIT_USAGE
+	}
+	*Flagdryrun = opts.DryRun
+	if opts.IfFree != "" {
+		freeThreshold, _ = parseFreeThreshold(opts.IfFree)
+	}
+
+	logInfo(tr("daemon.started", iv.Every))
+	for {
+		daemonRun(opts.Preset)
+		logInfo(tr("daemon.next", time.Now().Add(iv.Every).Format("2006-01-02 15:04:05")))
+		time.Sleep(iv.Every)
+	}
+}
+
+// daemonRun performs one scheduled cleanup and logs its result
+func daemonRun(preset string) {
+	existing := scanForExisting()
+	selectForAuto(existing, preset)
+	cancel, done := startCancellable()
+	result := cleanPrograms(existing, CleanOptions{DryRun: *Flagdryrun, Cancel: cancel})
+	done()
+	exportMetrics(existing, &result)
+	notifyWebhooks(&result)
+
+	switch {
+	case result.Cancelled:
+		logWarn(tr("daemon.stopped",
+			formatMB(result.Bytes()), len(result.Programs), len(result.Pending)))
+		saveJournal(&result)
+		cc_exit(EXIT_ABORTED)
+	case result.NotNeeded:
+		logOK(tr("daemon.not_needed", freeThreshold))
+	case len(result.Programs) == 0:
+		logWarn(tr("daemon.nothing"))
+	case len(result.Errors()) > 0:
+		logWarn(tr("daemon.finished_errors",
+			formatMB(result.Bytes()), len(result.Programs), result.Duration.Round(time.Millisecond), len(result.Errors())))
+	default:
+		logOK(tr("daemon.finished",
+			formatMB(result.Bytes()), len(result.Programs), result.Duration.Round(time.Millisecond)))
+	}
+}
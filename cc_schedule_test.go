@@ -0,0 +1,49 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIntervalSchtasks(t *testing.T) {
+	tests := map[string]string{
+		"weekly": "/SC WEEKLY",
+		"30m":    "/SC MINUTE /MO 30",
+		"90m":    "/SC MINUTE /MO 90",
+		"6h":     "/SC HOURLY /MO 6",
+		"24h":    "/SC DAILY /MO 1",
+		"72h":    "/SC DAILY /MO 3",
+		"36h":    "",
+		"8784h":  "",
+		"90s":    "",
+	}
+	for in, want := range tests {
+		iv, err := parseInterval(in)
+		if err != nil {
+			t.Fatalf("parseInterval(%q): %v", in, err)
+		}
+		args, err := iv.schtasks()
+		if got := strings.Join(args, " "); got != want || (err == nil) != (want != "") {
+			t.Errorf("schtasks(%s) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+}
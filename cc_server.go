@@ -0,0 +1,425 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cleanJob is a cleanup started through the API. Only one job runs at a time.
+type cleanJob struct {
+	ID     string
+	DryRun bool
+
+	mu       sync.Mutex
+	progress *CleanProgress
+	result   *CleanResult
+	subs     map[chan CleanProgress]bool
+}
+
+// JobStatus is the JSON form of a cleanJob
+type JobStatus struct {
+	ID       string         `json:"id"`
+	State    string         `json:"state"` // "running" or "done"
+	DryRun   bool           `json:"dry_run"`
+	Progress *CleanProgress `json:"progress,omitempty"`
+	Result   *HistoryEntry  `json:"result,omitempty"`
+	ExitCode *int           `json:"exit_code,omitempty"`
+}
+
+func (j *cleanJob) status() JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	s := JobStatus{ID: j.ID, State: "running", DryRun: j.DryRun, Progress: j.progress}
+	if j.result != nil {
+		e := newHistoryEntry(j.result)
+		code := j.result.ExitCode()
+		s.State, s.Result, s.ExitCode = "done", &e, &code
+	}
+	return s
+}
+
+// publish stores the latest progress and hands it to every subscriber that keeps up
+func (j *cleanJob) publish(p CleanProgress) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.progress = &p
+	for ch := range j.subs {
+		select {
+		case ch <- p:
+		default: // Slow client, it gets the next update
+		}
+	}
+}
+
+func (j *cleanJob) finish(r CleanResult) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.result = &r
+	for ch := range j.subs {
+		close(ch)
+	}
+	j.subs = nil
+}
+
+// subscribe returns a channel of progress updates, closed when the job is done (nil if it already is)
+func (j *cleanJob) subscribe() chan CleanProgress {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.result != nil {
+		return nil
+	}
+	ch := make(chan CleanProgress, 64)
+	j.subs[ch] = true
+	return ch
+}
+
+func (j *cleanJob) unsubscribe(ch chan CleanProgress) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.subs[ch] {
+		delete(j.subs, ch)
+		close(ch)
+	}
+}
+
+// apiServer holds the state of 'crunchycleaner serve'
+type apiServer struct {
+	token string
+
+	mu      sync.Mutex
+	jobs    map[string]*cleanJob
+	order   []string // Job ids, oldest first
+	running *cleanJob
+}
+
+const MAX_JOBS = 20 // Finished jobs kept for GET /v1/jobs/{id}
+
+func newAPIServer(token string) *apiServer {
+	return &apiServer{token: token, jobs: map[string]*cleanJob{}}
+}
+
+func (s *apiServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/catalog", s.handleCatalog)
+	mux.HandleFunc("GET /v1/scan", s.handleScan)
+	mux.HandleFunc("GET /v1/history", s.handleHistory)
+	mux.HandleFunc("POST /v1/clean", s.handleClean)
+	mux.HandleFunc("GET /v1/jobs/{id}", s.handleJob)
+	mux.HandleFunc("GET /v1/jobs/{id}/events", s.handleJobEvents)
+	return s.auth(mux)
+}
+
+// auth requires "Authorization: Bearer <token>" on every request
+func (s *apiServer) auth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "missing or invalid token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
+
+// CatalogEntry is a Program as returned by /v1/catalog and /v1/scan
+type CatalogEntry struct {
+	Name     string     `json:"name"`
+	Category string     `json:"category"`
+	Paths    []string   `json:"paths"`
+	Size     *int64     `json:"size,omitempty"`    // Only set by /v1/scan
+	Matches  []PathInfo `json:"matches,omitempty"` // Only set by /v1/scan
+}
+
+func (s *apiServer) handleCatalog(w http.ResponseWriter, r *http.Request) {
+	var list []CatalogEntry
+	for _, p := range getPrograms() {
+		list = append(list, CatalogEntry{Name: p.Name, Category: p.Category, Paths: p.Paths})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"version": catalogVersion(), "programs": list})
+}
+
+func (s *apiServer) handleScan(w http.ResponseWriter, r *http.Request) {
+	var list []CatalogEntry
+	for _, p := range scanForExisting() {
+		size := p.Size
+		list = append(list, CatalogEntry{Name: p.Name, Category: p.Category, Paths: p.Paths, Size: &size, Matches: p.Matches})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"programs": list})
+}
+
+func (s *apiServer) handleHistory(w http.ResponseWriter, r *http.Request) {
+	entries, err := loadHistory()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n >= 0 && n < len(entries) {
+		entries = entries[len(entries)-n:]
+	}
+	if entries == nil {
+		entries = []HistoryEntry{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"sessions": entries})
+}
+
+// CleanRequest is the body of POST /v1/clean, either programs or preset must be set
+type CleanRequest struct {
+	Programs []string `json:"programs"`
+	Preset   string   `json:"preset"`
+	DryRun   bool     `json:"dry_run"`
+}
+
+func (s *apiServer) handleClean(w http.ResponseWriter, r *http.Request) {
+	var req CleanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid body: %w", err))
+		return
+	}
+
+	existing := scanForExisting()
+	if req.Preset != "" {
+		if err := applyPreset(existing, req.Preset); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	checkNames(existing, req.Programs, false)
+	selected := 0
+	for _, p := range existing {
+		if p.Checked {
+			selected++
+		}
+	}
+	if selected == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("nothing selected (unknown programs or none found on this system)"))
+		return
+	}
+
+	s.mu.Lock()
+	if s.running != nil {
+		id := s.running.ID
+		s.mu.Unlock()
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "a cleanup is already running", "id": id})
+		return
+	}
+	job := &cleanJob{ID: newJobID(), DryRun: req.DryRun, subs: map[chan CleanProgress]bool{}}
+	s.running = job
+	s.jobs[job.ID] = job
+	s.order = append(s.order, job.ID)
+	for len(s.order) > MAX_JOBS {
+		delete(s.jobs, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.mu.Unlock()
+
+	go func() {
+		logInfo(tr("api.started", job.ID, selected, job.DryRun))
+		// Like in the terminal, an interrupt stops the cleanup after the current path first
+		cancel, done := startCancellable()
+		result := cleanPrograms(existing, CleanOptions{DryRun: job.DryRun, Progress: job.publish, Cancel: cancel})
+		done()
+		exportMetrics(existing, &result)
+		job.finish(result)
+		s.mu.Lock()
+		s.running = nil
+		s.mu.Unlock()
+		notifyWebhooks(&result)
+		logInfo(tr("api.finished", job.ID, formatMB(result.Bytes())))
+		if result.Cancelled {
+			// The interrupt was meant for the server, which quits once the rest is saved for 'resume'
+			logWarn(tr("clean.cancelled", len(result.Pending)))
+			saveJournal(&result)
+			cc_exit(EXIT_ABORTED)
+		}
+	}()
+
+	w.Header().Set("Location", "/v1/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, job.status())
+}
+
+func (s *apiServer) job(r *http.Request) *cleanJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jobs[r.PathValue("id")]
+}
+
+func (s *apiServer) handleJob(w http.ResponseWriter, r *http.Request) {
+	job := s.job(r)
+	if job == nil {
+		writeError(w, http.StatusNotFound, errors.New("unknown job"))
+		return
+	}
+	writeJSON(w, http.StatusOK, job.status())
+}
+
+// handleJobEvents streams "progress" server-sent events and a final "done" event with the result
+func (s *apiServer) handleJobEvents(w http.ResponseWriter, r *http.Request) {
+	job := s.job(r)
+	if job == nil {
+		writeError(w, http.StatusNotFound, errors.New("unknown job"))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming not supported"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(event string, v any) {
+		data, _ := json.Marshal(v)
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+		flusher.Flush()
+	}
+
+	ch := job.subscribe()
+	if st := job.status(); st.Progress != nil {
+		send("progress", st.Progress)
+	}
+	for ch != nil {
+		select {
+		case p, ok := <-ch:
+			if !ok {
+				ch = nil
+				continue
+			}
+			send("progress", p)
+		case <-r.Context().Done():
+			job.unsubscribe(ch)
+			return
+		}
+	}
+	send("done", job.status())
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ========================= SERVE COMMAND =========================
+
+// serveToken returns the API token from $CRUNCHYCLEANER_TOKEN, the config or the token file.
+// If none exists, a random token is generated and stored in the token file.
+func serveToken() (token, source string, err error) {
+	if t := os.Getenv("CRUNCHYCLEANER_TOKEN"); t != "" {
+		return t, "$CRUNCHYCLEANER_TOKEN", nil
+	}
+	if cfg.ServeToken != "" {
+		return cfg.ServeToken, cfg.Path, nil
+	}
+	path := filepath.Join(stateDir(), "serve.token")
+	data, err := os.ReadFile(path)
+	if err == nil && strings.TrimSpace(string(data)) != "" {
+		return strings.TrimSpace(string(data)), path, nil
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", "", err
+	}
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = hex.EncodeToString(b)
+	if err := os.MkdirAll(stateDir(), 0o755); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(path, []byte(token+"\n"), 0o600); err != nil {
+		return "", "", err
+	}
+	return token, path, nil
+}
+
+// serveCommand implements 'crunchycleaner serve'
+func serveCommand(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	listen := fs.String("listen", "127.0.0.1:8377", "Loopback address to listen on")
+	socket := fs.String("socket", "", "Listen on this Unix socket instead of TCP")
+	if err := fs.Parse(args); err != nil {
+		return EXIT_USAGE
+	}
+
+	token, source, err := serveToken()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "serve: %v\n", err)
+		return EXIT_PARTIAL
+	}
+
+	var ln net.Listener
+	if *socket != "" {
+		// A stale socket from a previous run is replaced, anything else at that path is left alone
+		if info, statErr := os.Lstat(*socket); statErr == nil {
+			if info.Mode()&os.ModeSocket == 0 {
+				fmt.Fprintf(os.Stderr, "serve: %s exists and is not a socket\n", *socket)
+				return EXIT_USAGE
+			}
+			os.Remove(*socket)
+		}
+		ln, err = listenSocket(*socket)
+	} else {
+		host, _, splitErr := net.SplitHostPort(*listen)
+		if ip := net.ParseIP(host); splitErr != nil || (host != "localhost" && (ip == nil || !ip.IsLoopback())) {
+			fmt.Fprintf(os.Stderr, "serve: %q is not a loopback address, use -socket or 127.0.0.1:<port>\n", *listen)
+			return EXIT_USAGE
+		}
+		ln, err = net.Listen("tcp", *listen)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "serve: %v\n", err)
+		return EXIT_PARTIAL
+	}
+
+	logInfo(tr("api.serving", ln.Addr(), source))
+	srv := &http.Server{Handler: newAPIServer(token).routes(), ReadHeaderTimeout: 10 * time.Second}
+	if err := srv.Serve(ln); err != nil {
+		fmt.Fprintf(os.Stderr, "serve: %v\n", err)
+		return EXIT_PARTIAL
+	}
+	return EXIT_OK
+}
@@ -0,0 +1,35 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+//go:build darwin || dragonfly || freebsd || linux
+
+package main
+
+import "golang.org/x/sys/unix"
+
+// statfs returns the block count, available blocks and block size of the filesystem holding path.
+// The field types differ between the platforms, hence the conversions.
+func statfs(path string) (blocks, avail, bsize uint64, err error) {
+	var st unix.Statfs_t
+	if err = unix.Statfs(path, &st); err != nil {
+		return 0, 0, 0, err
+	}
+	// Bavail is signed on the BSDs and negative when the reserved blocks are in use
+	return uint64(st.Blocks), uint64(max(int64(st.Bavail), 0)), uint64(st.Bsize), nil
+}
@@ -0,0 +1,34 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+//go:build netbsd
+
+package main
+
+import "golang.org/x/sys/unix"
+
+// statfs returns the block count, available blocks and block size of the filesystem holding path.
+// NetBSD only has statvfs, where the blocks are counted in fragments.
+func statfs(path string) (blocks, avail, bsize uint64, err error) {
+	var st unix.Statvfs_t
+	if err = unix.Statvfs(path, &st); err != nil {
+		return 0, 0, 0, err
+	}
+	return st.Blocks, st.Bavail, st.Frsize, nil
+}
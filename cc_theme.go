@@ -0,0 +1,172 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Colors of the active theme. The names are those of the default palette,
+// other themes only keep the roles: YELLOW for accents and warnings, CYAN for headings and info, GREEN for success.
+// All of them are empty when colors are off.
+var (
+	YELLOW = "\033[33m"
+	CYAN   = "\033[36m"
+	GREEN  = "\033[32m"
+	RC     = "\033[0m" // Reset Color
+)
+
+// Terminal control sequences, empty when stdout is not a terminal
+var (
+	CLEAR_SCREEN = "\033[H\033[2J"
+	CLEAR_LINE   = "\r\033[K"
+	CLEAR_BELOW  = "\033[J"
+	SHOW_CURSOR  = "\033[?25h"
+)
+
+// termControl is false when stdout is not a terminal, so nothing redraws, animates or moves the cursor
+var termControl = true
+
+// Theme assigns a color to every role, written like in the config ("bold yellow", "208", "#ff8800")
+type Theme struct {
+	Accent string
+	Info   string
+	OK     string
+}
+
+// themes are the built-in palettes, [theme] in the config can override single roles
+var themes = map[string]Theme{
+	"default":       {Accent: "yellow", Info: "cyan", OK: "green"},
+	"light":         {Accent: "blue", Info: "magenta", OK: "green"}, // For light terminal backgrounds
+	"high-contrast": {Accent: "bold bright-yellow", Info: "bold bright-cyan", OK: "bold bright-green"},
+	"mono":          {Accent: "bold", Info: "underline", OK: "bold"},
+}
+
+// themeNames returns the built-in theme names in alphabetical order
+func themeNames() []string {
+	var names []string
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// setupTheme decides whether colors and terminal control are used and sets the palette.
+// mode is "auto", "always" or "never". In auto mode colors are off for NO_COLOR, TERM=dumb, the accessible mode
+// and when stdout is no terminal.
+func setupTheme(mode, name string, c *Config) error {
+	tty := isTerminal()
+	dumb := os.Getenv("TERM") == "dumb"
+	termControl = tty && !dumb && !*Flagaccessible
+
+	var color bool
+	switch mode {
+	case "", "auto":
+		color = termControl && os.Getenv("NO_COLOR") == ""
+	case "always":
+		color = true
+	case "never":
+	default:
+		return fmt.Errorf("expected auto, always or never, got %q", mode)
+	}
+
+	if !termControl {
+		CLEAR_SCREEN, CLEAR_LINE, CLEAR_BELOW, SHOW_CURSOR = "", "", "", ""
+	}
+	t, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(themeNames(), ", "))
+	}
+	if !color {
+		YELLOW, CYAN, GREEN, RC = "", "", "", ""
+		return nil
+	}
+	if c.ThemeAccent != "" {
+		t.Accent = c.ThemeAccent
+	}
+	if c.ThemeInfo != "" {
+		t.Info = c.ThemeInfo
+	}
+	if c.ThemeOK != "" {
+		t.OK = c.ThemeOK
+	}
+	var errs [3]error
+	YELLOW, errs[0] = parseColor(t.Accent)
+	CYAN, errs[1] = parseColor(t.Info)
+	GREEN, errs[2] = parseColor(t.OK)
+	return errors.Join(errs[:]...)
+}
+
+// cursorTo moves the cursor to the start of a screen row (1-based)
+func cursorTo(row int) string {
+	if !termControl {
+		return ""
+	}
+	return fmt.Sprintf("\033[%d;1H", row)
+}
+
+// isTerminal reports whether stdout is a terminal
+func isTerminal() bool {
+	_, _, err := termSize()
+	return err == nil
+}
+
+var colorNames = map[string]int{"black": 0, "red": 1, "green": 2, "yellow": 3, "blue": 4, "magenta": 5, "cyan": 6, "white": 7}
+
+// parseColor turns a color spec into an SGR sequence. A spec is a space separated list of
+// "bold", "underline" and one color: a name, "bright-<name>", a 256-color number or "#rrggbb".
+func parseColor(spec string) (string, error) {
+	var codes []string
+	for _, w := range strings.Fields(strings.ToLower(spec)) {
+		if n, ok := colorNames[strings.TrimPrefix(w, "bright-")]; ok {
+			if strings.HasPrefix(w, "bright-") {
+				n += 60
+			}
+			codes = append(codes, strconv.Itoa(30+n))
+			continue
+		}
+		if n, err := strconv.Atoi(w); err == nil && n >= 0 && n <= 255 {
+			codes = append(codes, "38;5;"+w)
+			continue
+		}
+		if rgb, err := strconv.ParseUint(strings.TrimPrefix(w, "#"), 16, 32); err == nil && len(w) == 7 && w[0] == '#' {
+			codes = append(codes, fmt.Sprintf("38;2;%d;%d;%d", rgb>>16, rgb>>8&0xff, rgb&0xff))
+			continue
+		}
+		switch w {
+		case "bold":
+			codes = append(codes, "1")
+		case "underline":
+			codes = append(codes, "4")
+		default:
+			return "", fmt.Errorf("invalid color %q in %q", w, spec)
+		}
+	}
+	if len(codes) == 0 {
+		return "", nil
+	}
+	return "\033[" + strings.Join(codes, ";") + "m", nil
+}
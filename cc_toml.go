@@ -0,0 +1,385 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+// A tiny TOML subset reader/writer, so the config doesn't pull in a dependency.
+// Supported: [tables] and [dotted."quoted".tables], bare and quoted keys,
+// strings, integers, floats, booleans and (multi-line) arrays of those.
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// tomlTable is one [table] section with its key/value pairs
+type tomlTable struct {
+	Name []string // Header parts, e.g. [presets.dev] -> ["presets", "dev"]
+	Keys map[string]any
+}
+
+type tomlParser struct {
+	s    string
+	i    int
+	line int
+}
+
+// parseTOML parses data into a list of tables. Keys before the first header land in a table without name.
+func parseTOML(data string) ([]*tomlTable, error) {
+	p := &tomlParser{s: data, line: 1}
+	cur := &tomlTable{Keys: map[string]any{}}
+	tables := []*tomlTable{cur}
+
+	for {
+		p.skipSpace(true)
+		if p.i >= len(p.s) {
+			break
+		}
+		if p.s[p.i] == '[' {
+			p.i++
+			if p.peek() == '[' {
+				return nil, p.errorf("arrays of tables are not supported")
+			}
+			name, err := p.parseKeyPath()
+			if err != nil {
+				return nil, err
+			}
+			if p.peek() != ']' {
+				return nil, p.errorf("expected ']'")
+			}
+			p.i++
+			cur = &tomlTable{Name: name, Keys: map[string]any{}}
+			tables = append(tables, cur)
+		} else {
+			key, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace(false)
+			if p.peek() != '=' {
+				return nil, p.errorf("expected '=' after key %q", key)
+			}
+			p.i++
+			p.skipSpace(false)
+			val, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := cur.Keys[key]; dup {
+				return nil, p.errorf("duplicate key %q", key)
+			}
+			cur.Keys[key] = val
+		}
+		if err := p.endOfLine(); err != nil {
+			return nil, err
+		}
+	}
+	return tables, nil
+}
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *tomlParser) peek() byte {
+	if p.i >= len(p.s) {
+		return 0
+	}
+	return p.s[p.i]
+}
+
+// skipSpace skips blanks and comments, and newlines too if newlines is set
+func (p *tomlParser) skipSpace(newlines bool) {
+	for p.i < len(p.s) {
+		switch c := p.s[p.i]; {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.i++
+		case c == '\n' && newlines:
+			p.line++
+			p.i++
+		case c == '#':
+			for p.i < len(p.s) && p.s[p.i] != '\n' {
+				p.i++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) endOfLine() error {
+	p.skipSpace(false)
+	if p.i < len(p.s) && p.s[p.i] != '\n' {
+		return p.errorf("unexpected %q", p.s[p.i])
+	}
+	return nil
+}
+
+func (p *tomlParser) parseKeyPath() ([]string, error) {
+	var parts []string
+	for {
+		p.skipSpace(false)
+		k, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, k)
+		p.skipSpace(false)
+		if p.peek() != '.' {
+			return parts, nil
+		}
+		p.i++
+	}
+}
+
+func (p *tomlParser) parseKey() (string, error) {
+	if c := p.peek(); c == '"' || c == '\'' {
+		return p.parseString()
+	}
+	start := p.i
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' {
+			p.i++
+			continue
+		}
+		break
+	}
+	if start == p.i {
+		return "", p.errorf("expected key")
+	}
+	return p.s[start:p.i], nil
+}
+
+func (p *tomlParser) parseValue() (any, error) {
+	switch c := p.peek(); {
+	case c == '"' || c == '\'':
+		return p.parseString()
+	case c == '[':
+		return p.parseArray()
+	case strings.HasPrefix(p.s[p.i:], "true"):
+		p.i += 4
+		return true, nil
+	case strings.HasPrefix(p.s[p.i:], "false"):
+		p.i += 5
+		return false, nil
+	}
+
+	start := p.i
+	for p.i < len(p.s) && strings.IndexByte("+-0123456789._eE", p.s[p.i]) >= 0 {
+		p.i++
+	}
+	raw := strings.ReplaceAll(p.s[start:p.i], "_", "")
+	if raw == "" {
+		return nil, p.errorf("expected value")
+	}
+	if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(raw, 64); err == nil {
+		return f, nil
+	}
+	return nil, p.errorf("invalid value %q", raw)
+}
+
+func (p *tomlParser) parseString() (string, error) {
+	quote := p.s[p.i]
+	p.i++
+	var b strings.Builder
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		p.i++
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case c == '\n':
+			return "", p.errorf("unterminated string")
+		case c == '\\' && quote == '"':
+			if p.i >= len(p.s) {
+				return "", p.errorf("unterminated string")
+			}
+			e := p.s[p.i]
+			p.i++
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case '"', '\\':
+				b.WriteByte(e)
+			default:
+				return "", p.errorf("unsupported escape \\%c", e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *tomlParser) parseArray() ([]any, error) {
+	p.i++ // '['
+	arr := []any{}
+	for {
+		p.skipSpace(true)
+		if p.peek() == ']' {
+			p.i++
+			return arr, nil
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, v)
+		p.skipSpace(true)
+		switch p.peek() {
+		case ',':
+			p.i++
+		case ']':
+		default:
+			return nil, p.errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+// tomlQuote formats s as a TOML basic string
+func tomlQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// tomlKey quotes a key only when it isn't a valid bare key
+func tomlKey(k string) string {
+	if k == "" {
+		return `""`
+	}
+	for _, c := range k {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return tomlQuote(k)
+		}
+	}
+	return k
+}
+
+// tomlStrings formats a string slice as a TOML array
+func tomlStrings(list []string) string {
+	quoted := make([]string, len(list))
+	for i, s := range list {
+		quoted[i] = tomlQuote(s)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// Typed accessors, returning an error when the value has the wrong type
+
+func (t *tomlTable) str(key string, dst *string) error {
+	v, ok := t.Keys[key]
+	if !ok {
+		return nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("%s: expected string", key)
+	}
+	*dst = s
+	return nil
+}
+
+func (t *tomlTable) boolean(key string, dst *bool) error {
+	v, ok := t.Keys[key]
+	if !ok {
+		return nil
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return fmt.Errorf("%s: expected true/false", key)
+	}
+	*dst = b
+	return nil
+}
+
+func (t *tomlTable) integer(key string, dst *int) error {
+	v, ok := t.Keys[key]
+	if !ok {
+		return nil
+	}
+	n, ok := v.(int64)
+	if !ok {
+		return fmt.Errorf("%s: expected integer", key)
+	}
+	*dst = int(n)
+	return nil
+}
+
+func (t *tomlTable) stringList(key string, dst *[]string) error {
+	v, ok := t.Keys[key]
+	if !ok {
+		return nil
+	}
+	arr, ok := v.([]any)
+	if !ok {
+		return fmt.Errorf("%s: expected array of strings", key)
+	}
+	out := make([]string, 0, len(arr))
+	for _, e := range arr {
+		s, ok := e.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected array of strings", key)
+		}
+		out = append(out, s)
+	}
+	*dst = out
+	return nil
+}
+
+// removeTOMLTable drops the table with the given header and all of its keys from data
+func removeTOMLTable(data string, name []string) string {
+	lines := strings.SplitAfter(data, "\n")
+	var out, pending []string
+	skipping := false
+	for _, l := range lines {
+		trimmed := strings.TrimSpace(l)
+		if strings.HasPrefix(trimmed, "[") {
+			// [[arrays of tables]] are never removed, but they end the table before them
+			p := &tomlParser{s: trimmed, i: 1}
+			header, err := p.parseKeyPath()
+			if skipping {
+				// Comments right above the next header belong to that table
+				out = append(out, pending...)
+			}
+			skipping = err == nil && !strings.HasPrefix(trimmed, "[[") && slices.Equal(header, name)
+		} else if skipping && (trimmed == "" || strings.HasPrefix(trimmed, "#")) {
+			pending = append(pending, l)
+			continue
+		}
+		pending = nil
+		if !skipping {
+			out = append(out, l)
+		}
+	}
+	text := strings.TrimRight(strings.Join(out, ""), "\n \t")
+	if text == "" {
+		return ""
+	}
+	return text + "\n"
+}
@@ -0,0 +1,162 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want []*tomlTable
+	}{
+		{
+			name: "empty",
+			doc:  "",
+			want: []*tomlTable{{Keys: map[string]any{}}},
+		},
+		{
+			name: "scalars and comments",
+			doc:  "# comment\nname = \"x\" # trailing\ncount = 1_000\nratio = 0.5\non = true\noff = false\n",
+			want: []*tomlTable{{Keys: map[string]any{"name": "x", "count": int64(1000), "ratio": 0.5, "on": true, "off": false}}},
+		},
+		{
+			name: "strings and escapes",
+			doc:  `basic = "tab\there \"quoted\" back\\slash\nnew"` + "\n" + `literal = 'C:\Temp\*'` + "\n" + `"quoted key" = ""` + "\n",
+			want: []*tomlTable{{Keys: map[string]any{
+				"basic":      "tab\there \"quoted\" back\\slash\nnew",
+				"literal":    `C:\Temp\*`,
+				"quoted key": "",
+			}}},
+		},
+		{
+			name: "arrays",
+			doc:  "empty = []\nmixed = [1, \"two\", true]\nlong = [\n  \"a\", # first\n  \"b\",\n]\n",
+			want: []*tomlTable{{Keys: map[string]any{
+				"empty": []any{},
+				"mixed": []any{int64(1), "two", true},
+				"long":  []any{"a", "b"},
+			}}},
+		},
+		{
+			name: "tables",
+			doc:  "top = 1\n[ui]\nmouse = true\n[presets.\"my dev\"]\nprograms = [\"Go\"]\n",
+			want: []*tomlTable{
+				{Keys: map[string]any{"top": int64(1)}},
+				{Name: []string{"ui"}, Keys: map[string]any{"mouse": true}},
+				{Name: []string{"presets", "my dev"}, Keys: map[string]any{"programs": []any{"Go"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTOML(tt.doc)
+			if err != nil {
+				t.Fatalf("parseTOML: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				for i := range got {
+					t.Logf("got[%d] = %+v", i, *got[i])
+				}
+				t.Errorf("parseTOML(%q) mismatch", tt.doc)
+			}
+		})
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want string
+	}{
+		{`a = "open`, "line 1: unterminated string"},
+		{"a = \"split\nline\"", "line 1: unterminated string"},
+		{"a = 1\nb = \"\\q\"", `line 2: unsupported escape \q`},
+		{"a = 1\na = 2", `line 2: duplicate key "a"`},
+		{"x 1", `line 1: expected '=' after key "x"`},
+		{"= 1", "line 1: expected key"},
+		{"a = nope", "line 1: expected value"},
+		{"a = 1.2.3", `line 1: invalid value "1.2.3"`},
+		{"a = 1 b", `line 1: unexpected 'b'`},
+		{"a = [1, 2\n3]", "line 2: expected ',' or ']' in array"},
+		{"\n\n[table\n", "line 3: expected ']'"},
+		{"a = 1\n[[table]]\n", "line 2: arrays of tables are not supported"},
+	}
+	for _, tt := range tests {
+		_, err := parseTOML(tt.doc)
+		if err == nil {
+			t.Errorf("parseTOML(%q) succeeded, want %q", tt.doc, tt.want)
+		} else if err.Error() != tt.want {
+			t.Errorf("parseTOML(%q) = %q, want %q", tt.doc, err, tt.want)
+		}
+	}
+}
+
+func TestLoadConfigReportsLine(t *testing.T) {
+	tests := map[string]string{
+		"[ui]\nmouse = true\n\n[defaults]\nselected = [\"a\" \"b\"]\n": "line 5: expected ',' or ']' in array",
+		"[ui]\nmouse = true\n[[presets.dev]]\nprograms = []\n":         "line 3: arrays of tables are not supported",
+	}
+	for doc, want := range tests {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		os.WriteFile(path, []byte(doc), 0o644)
+		_, err := loadConfig(path)
+		if err == nil || !strings.HasPrefix(err.Error(), path+": ") || !strings.HasSuffix(err.Error(), want) {
+			t.Errorf("loadConfig(%q) = %v, want %q", doc, err, path+": "+want)
+		}
+	}
+}
+
+func TestRemoveTOMLTable(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{
+			name: "middle",
+			doc:  "[ui]\nmouse = true\n\n[presets.dev]\nprograms = [\"Go\"]\n# Logging\n[log.file]\npath = \"x\"\n",
+			want: "[ui]\nmouse = true\n\n# Logging\n[log.file]\npath = \"x\"\n",
+		},
+		{
+			name: "last",
+			doc:  "[ui]\nmouse = true\n\n[presets.dev]\nprograms = [\"Go\"]\n",
+			want: "[ui]\nmouse = true\n",
+		},
+		{
+			name: "followed by an array of tables",
+			doc:  "[presets.dev]\nprograms = [\"Go\"]\n[[presets.dev]]\nprograms = []\n",
+			want: "[[presets.dev]]\nprograms = []\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := removeTOMLTable(tt.doc, []string{"presets", "dev"}); got != tt.want {
+				t.Errorf("removeTOMLTable(%q) = %q, want %q", tt.doc, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Layout limits of the menu
+const (
+	MIN_LIST_ROWS = 5 // List entries that must stay visible before banner and detail pane are hidden
+	DETAIL_ROWS   = 5 // Height of the detail pane: a title and up to four paths
+)
+
+// screenSize returns the terminal size, or COLS x LINES if it can't be read (e.g. output is piped)
+func screenSize() (cols, rows int) {
+	cols, rows, err := termSize()
+	if err != nil || cols <= 0 || rows <= 0 {
+		return COLS, LINES
+	}
+	return cols, rows
+}
+
+// viewport is the visible part of a list that may be longer than the terminal
+type viewport struct {
+	top    int // Index of the first visible entry
+	height int // Number of visible entries
+	row    int // Screen row (1-based) the list starts at
+	detail int // Rows reserved for the detail pane below the list, 0 if it doesn't fit
+	button int // Screen row of the clickable clean button, 0 if there is none
+}
+
+// follow scrolls the viewport as little as possible so that entry idx of n is visible
+func (v *viewport) follow(idx, n int) {
+	v.height = max(v.height, 1)
+	if idx < v.top {
+		v.top = idx
+	}
+	if idx >= v.top+v.height {
+		v.top = idx - v.height + 1
+	}
+	v.top = max(0, min(v.top, n-v.height))
+}
+
+// end returns the index after the last visible entry
+func (v *viewport) end(n int) int {
+	return min(v.top+v.height, n)
+}
+
+// fitWidth cuts s to width visible characters. ANSI escape sequences don't count
+// towards the width and are kept, so colors are still reset after a cut.
+func fitWidth(s string, width int) string {
+	var b strings.Builder
+	visible := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\033' {
+			// Copy the whole sequence up to its final letter
+			j := i + 1
+			for j < len(s) && !(s[j] >= 'A' && s[j] <= 'Z' || s[j] >= 'a' && s[j] <= 'z') {
+				j++
+			}
+			b.WriteString(s[i:min(j+1, len(s))])
+			i = j + 1
+			continue
+		}
+		if visible == width {
+			b.WriteString(RC)
+			return b.String()
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		b.WriteString(s[i : i+size])
+		visible++
+		i += size
+	}
+	return b.String()
+}
+
+// PROGRESS_BAR_WIDTH is the number of cells in the progress bar
+const PROGRESS_BAR_WIDTH = 20
+
+// newProgressBar returns a CleanOptions.Progress callback that draws a progress line
+// with the bytes and files handled against the scan totals, the throughput and an ETA.
+// It returns nil when stdout is not a terminal, a line redrawn in place would only clutter a log.
+func newProgressBar() func(CleanProgress) {
+	if !termControl {
+		return nil
+	}
+	start := time.Now()
+	return func(p CleanProgress) {
+		cols, _ := screenSize()
+		setProgressLine(fitWidth(formatProgress(p, time.Since(start)), cols))
+	}
+}
+
+// formatProgress renders a progress update as a single line
+func formatProgress(p CleanProgress, elapsed time.Duration) string {
+	done := 0.0
+	switch {
+	case p.TotalBytes > 0:
+		done = float64(p.Bytes) / float64(p.TotalBytes)
+	case p.TotalFiles > 0:
+		done = float64(p.Files) / float64(p.TotalFiles)
+	case p.Total > 0:
+		done = float64(p.Index-1) / float64(p.Total)
+	}
+	done = min(max(done, 0), 1)
+	filled := int(done * PROGRESS_BAR_WIDTH)
+
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(p.Bytes) / elapsed.Seconds()
+	}
+	eta := "--"
+	if rate > 0 && p.TotalBytes > p.Bytes {
+		eta = time.Duration(float64(p.TotalBytes-p.Bytes) / rate * float64(time.Second)).Round(time.Second).String()
+	}
+
+	// Most important first, the line is cut at the terminal width
+	return fmt.Sprintf("%s[%s%s]%s %3.0f%% %s%s%s (%d/%d) | %s / %s | %s | %s/s | %s",
+		YELLOW, strings.Repeat("#", filled), strings.Repeat("-", PROGRESS_BAR_WIDTH-filled), RC, done*100,
+		CYAN, p.Program, RC, p.Index, p.Total, formatMB(p.Bytes), formatMB(p.TotalBytes),
+		tr("progress.files", p.Files, p.TotalFiles), formatMB(int64(rate)), tr("progress.eta", eta))
+}
@@ -0,0 +1,173 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Webhook is a [webhooks.<name>] target that receives a summary after every cleanup
+type Webhook struct {
+	Name      string
+	URL       string
+	Timeout   time.Duration // Per attempt
+	Retries   int           // Extra attempts after a failed one
+	OnFailure bool          // Only notify when the cleanup had errors
+}
+
+const (
+	WEBHOOK_TIMEOUT     = 10 * time.Second
+	WEBHOOK_RETRIES     = 3
+	WEBHOOK_MAX_RETRIES = 10
+	WEBHOOK_MAX_BACKOFF = 30 * time.Second
+	WEBHOOK_EXIT_WAIT   = 5 * time.Second // How long exiting waits for notifications still in flight
+)
+
+// webhookBackoff is the delay before the first retry, it doubles for every further one up to WEBHOOK_MAX_BACKOFF
+var webhookBackoff = time.Second
+
+// webhooksSending tracks the notifications still in flight, cc_exit waits for them
+var webhooksSending sync.WaitGroup
+
+// waitWebhooks waits up to limit for the notifications in flight and reports whether all of them finished
+func waitWebhooks(limit time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		webhooksSending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(limit):
+		return false
+	}
+}
+
+// WebhookPayload is the JSON body posted to every webhook
+type WebhookPayload struct {
+	Event    string `json:"event"` // Always "cleanup"
+	Host     string `json:"host"`
+	Version  string `json:"version"`
+	ExitCode int    `json:"exit_code"`
+	Bytes    int64  `json:"bytes"`
+	HistoryEntry
+	Failures []WebhookFailure `json:"failures"`
+}
+
+// WebhookFailure is one CleanError in the payload
+type WebhookFailure struct {
+	Program string `json:"program"`
+	Stage   string `json:"stage"`
+	Path    string `json:"path"`
+	Error   string `json:"error"`
+}
+
+func newWebhookPayload(r *CleanResult) WebhookPayload {
+	host, _ := os.Hostname()
+	p := WebhookPayload{
+		Event:        "cleanup",
+		Host:         host,
+		Version:      CC_VERSION,
+		ExitCode:     r.ExitCode(),
+		Bytes:        r.Bytes(),
+		HistoryEntry: newHistoryEntry(r),
+		Failures:     []WebhookFailure{},
+	}
+	p.Errors = nil // Listed in Failures with more detail
+	for _, pr := range r.Programs {
+		for _, err := range pr.Errors {
+			p.Failures = append(p.Failures, WebhookFailure{pr.Name, err.Stage, err.Path, err.Err.Error()})
+		}
+	}
+	return p
+}
+
+// notifyWebhooks posts the result of a cleanup to every configured webhook in the background and logs failures,
+// so a slow endpoint doesn't hold up the result screen
+func notifyWebhooks(r *CleanResult) {
+	if len(cfg.Webhooks) == 0 || r.NotNeeded || len(r.Programs) == 0 {
+		return
+	}
+	body, err := json.Marshal(newWebhookPayload(r))
+	if err != nil {
+		logWarn(tr("webhook.encode_error", err))
+		return
+	}
+	failed := len(r.Errors()) > 0
+	for _, w := range cfg.Webhooks {
+		if w.OnFailure && !failed {
+			continue
+		}
+		webhooksSending.Add(1)
+		go func() {
+			defer webhooksSending.Done()
+			if err := w.post(body); err != nil {
+				logWarn(tr("webhook.failed", w.Name, err))
+			} else {
+				logQuiet(LOG_INFO, "Webhook "+w.Name+" notified")
+			}
+		}()
+	}
+}
+
+// post sends body to the webhook, retrying network errors, 429 and 5xx with a growing delay
+func (w Webhook) post(body []byte) error {
+	client := &http.Client{Timeout: w.Timeout}
+	var err error
+	for attempt := 0; attempt <= w.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(min(webhookBackoff<<min(attempt-1, 10), WEBHOOK_MAX_BACKOFF))
+		}
+		var retry bool
+		if retry, err = w.send(client, body); err == nil || !retry {
+			return err
+		}
+	}
+	return fmt.Errorf("%w (gave up after %d attempts)", err, w.Retries+1)
+}
+
+// send makes a single attempt and reports whether a failure is worth retrying
+func (w Webhook) send(client *http.Client, body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "CrunchyCleaner/"+CC_VERSION)
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("%s answered %s", w.URL, resp.Status)
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
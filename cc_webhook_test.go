@@ -0,0 +1,181 @@
+// ##################################################################
+// CrunchyCleaner
+// Made by: Knuspii, (M)
+// Project: https://github.com/Knuspii/CrunchyCleaner
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+// ##################################################################
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// webhookServer answers with the given status codes in turn, the last one repeats
+func webhookServer(t *testing.T, codes ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		w.WriteHeader(codes[min(n, len(codes))-1])
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func fastBackoff(t *testing.T) {
+	saved := webhookBackoff
+	webhookBackoff = time.Millisecond
+	t.Cleanup(func() { webhookBackoff = saved })
+}
+
+func TestWebhookPayload(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	r := &CleanResult{Start: time.Now(), Programs: []ProgramResult{
+		{Name: "Pip Cache", Paths: 1, Bytes: 2048},
+		{Name: "NPM Cache", Paths: 2, Bytes: 4096, Errors: []CleanError{{"delete", "/x/y", fs.ErrPermission}}},
+	}}
+	body, _ := json.Marshal(newWebhookPayload(r))
+	if err := (Webhook{URL: srv.URL, Timeout: time.Second}).post(body); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+
+	for _, key := range []string{"event", "host", "version", "user", "time", "dry_run", "duration_seconds"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("payload has no %q", key)
+		}
+	}
+	if got["event"] != "cleanup" || got["exit_code"] != float64(EXIT_PERMISSION) || got["bytes"] != float64(6144) {
+		t.Errorf("event, exit_code, bytes = %v, %v, %v", got["event"], got["exit_code"], got["bytes"])
+	}
+	if programs, _ := got["programs"].([]any); len(programs) != 2 {
+		t.Errorf("programs = %v, want 2 entries", got["programs"])
+	}
+	failures, _ := got["failures"].([]any)
+	if len(failures) != 1 {
+		t.Fatalf("failures = %v, want 1 entry", got["failures"])
+	}
+	f := failures[0].(map[string]any)
+	if f["program"] != "NPM Cache" || f["stage"] != "delete" || f["path"] != "/x/y" || f["error"] == "" {
+		t.Errorf("failure = %v", f)
+	}
+	if _, ok := got["errors"]; ok {
+		t.Errorf("errors should only be listed in failures")
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	fastBackoff(t)
+	tests := []struct {
+		name    string
+		codes   []int
+		retries int
+		calls   int32
+		ok      bool
+	}{
+		{"success", []int{204}, 3, 1, true},
+		{"5xx is retried", []int{500, 503, 200}, 3, 3, true},
+		{"429 is retried", []int{429, 200}, 3, 2, true},
+		{"4xx is not retried", []int{400, 200}, 3, 1, false},
+		{"gives up", []int{502}, 2, 3, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := webhookServer(t, tt.codes...)
+			err := Webhook{URL: srv.URL, Timeout: time.Second, Retries: tt.retries}.post([]byte("{}"))
+			if (err == nil) != tt.ok {
+				t.Errorf("err = %v, want success %v", err, tt.ok)
+			}
+			if calls.Load() != tt.calls {
+				t.Errorf("%d attempts, want %d", calls.Load(), tt.calls)
+			}
+		})
+	}
+}
+
+func TestWebhookTimeout(t *testing.T) {
+	fastBackoff(t)
+	release := make(chan struct{})
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	start := time.Now()
+	err := Webhook{URL: srv.URL, Timeout: 50 * time.Millisecond, Retries: 1}.post([]byte("{}"))
+	var netErr interface{ Timeout() bool }
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("err = %v, want a timeout", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("%d attempts, want 2 (timeouts are retried)", calls.Load())
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("took %s, the timeout wasn't applied", d)
+	}
+}
+
+func TestWebhookConfigLimits(t *testing.T) {
+	for retries, ok := range map[string]bool{"0": true, "10": true, "-1": false, "11": false} {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		os.WriteFile(path, []byte("[webhooks.test]\nurl = \"http://127.0.0.1:1/\"\nretries = "+retries+"\n"), 0o644)
+		_, err := loadConfig(path)
+		if (err == nil) != ok {
+			t.Errorf("retries = %s: err = %v, want valid %v", retries, err, ok)
+		}
+		if err != nil && !strings.Contains(err.Error(), "retries") {
+			t.Errorf("retries = %s: error %q doesn't name the key", retries, err)
+		}
+	}
+}
+
+func TestWaitWebhooksLimit(t *testing.T) {
+	webhooksSending.Add(1)
+	start := time.Now()
+	if waitWebhooks(50 * time.Millisecond) {
+		t.Error("waitWebhooks reported a stuck notification as finished")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("waitWebhooks took %v", d)
+	}
+	webhooksSending.Done()
+	if !waitWebhooks(time.Second) {
+		t.Error("waitWebhooks timed out without notifications in flight")
+	}
+}
@@ -4,4 +4,4 @@ go 1.25.0
 
 require github.com/eiannone/keyboard v0.0.0-20220611211555-0d226195f203
 
-require golang.org/x/sys v0.42.0 // indirect
+require golang.org/x/sys v0.42.0